@@ -0,0 +1,165 @@
+package envconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// BatchLookup resolves many keys in a single call, e.g. one round trip to a secret manager.
+// Keys absent from the returned map are treated as unset.
+type BatchLookup interface {
+	LookupMany(ctx context.Context, keys []string) (map[string]string, error)
+}
+
+// BatchLookupFunc adapts a function to BatchLookup.
+type BatchLookupFunc func(ctx context.Context, keys []string) (map[string]string, error)
+
+// LookupMany calls f(ctx, keys).
+func (f BatchLookupFunc) LookupMany(ctx context.Context, keys []string) (map[string]string, error) {
+	return f(ctx, keys)
+}
+
+// WithBatchLookup reads in two phases: all keys of the holder (see Keys) are resolved with
+// a single LookupMany call first, then the struct is populated from the result.
+// Keys that can't be known upfront, like those read by an EnvCollector, are resolved
+// with a LookupMany call each. It replaces WithLookup and WithLookupContext.
+func WithBatchLookup(batch BatchLookup) Option {
+	return func(o *options) {
+		o.batch = batch
+	}
+}
+
+// ParallelLookup returns a BatchLookup resolving keys with up to concurrency parallel calls of lookup.
+// The first error cancels the remaining calls.
+func ParallelLookup(lookup LookupEnvContext, concurrency int) BatchLookup {
+	return BatchLookupFunc(func(ctx context.Context, keys []string) (map[string]string, error) {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		var (
+			mu     sync.Mutex
+			values = make(map[string]string, len(keys))
+			wg     sync.WaitGroup
+			sem    = make(chan struct{}, max(concurrency, 1))
+		)
+
+		for _, key := range keys {
+			wg.Go(func() {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					return
+				}
+
+				v, ok, err := lookup(ctx, key)
+				if err != nil {
+					cancel(fmt.Errorf("envconfig: lookup %q: %w", key, err))
+					return
+				}
+				if ok {
+					mu.Lock()
+					values[key] = v
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+		return values, nil
+	})
+}
+
+// Keys returns the fields Read would look up for T, in struct order.
+// The defaults and required flags are available via FieldInfo.Default and FieldInfo.Required.
+// Keys read by an EnvCollector can't be known upfront and are not included.
+func Keys[T any]() []FieldInfo {
+	var out []FieldInfo
+	planKeys(reflect.TypeFor[T](), "", "", &out)
+	return out
+}
+
+// planKeys walks t following the same rules as read, without validating tags.
+func planKeys(t reflect.Type, prefix, path string, out *[]FieldInfo) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if field.PkgPath != "" || promoted(t, field) {
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer && ft != locationPtrType {
+			ft = ft.Elem()
+		}
+		if reflect.PointerTo(ft).Implements(envCollectorType) {
+			continue
+		}
+
+		env, hasEnv := field.Tag.Lookup("env")
+		if env == "-" {
+			continue
+		}
+
+		if ft.Kind() == reflect.Struct && !hasEnv {
+			childPrefix := prefix
+			if pref, hasPrefix := field.Tag.Lookup("envPrefix"); hasPrefix {
+				childPrefix = prefix + pref + "_"
+			}
+			planKeys(ft, childPrefix, joinPath(path, field.Name), out)
+			continue
+		}
+
+		if env == "" {
+			continue
+		}
+
+		*out = append(*out, FieldInfo{
+			Key:  prefix + env,
+			Path: joinPath(path, field.Name),
+			Type: field.Type,
+			Tag:  field.Tag,
+		})
+	}
+}
+
+// batchLookup resolves the keys of t with o.batch and returns a lookup serving the result.
+func (o *options) batchLookup(t reflect.Type) (LookupEnvContext, error) {
+	var fields []FieldInfo
+	planKeys(t, "", "", &fields)
+
+	planned := make(map[string]bool, len(fields))
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !planned[f.Key] {
+			planned[f.Key] = true
+			keys = append(keys, f.Key)
+		}
+	}
+
+	values, err := o.batch.LookupMany(o.ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: batch lookup: %w", err)
+	}
+
+	return func(ctx context.Context, key string) (string, bool, error) {
+		if planned[key] {
+			v, ok := values[key]
+			return v, ok, nil
+		}
+
+		res, err := o.batch.LookupMany(ctx, []string{key})
+		if err != nil {
+			return "", false, err
+		}
+		v, ok := res[key]
+		return v, ok, nil
+	}, nil
+}
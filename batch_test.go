@@ -0,0 +1,165 @@
+package envconfig_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/struct0x/envconfig"
+)
+
+type batchConfig struct {
+	Name string `env:"NAME" envRequired:"true"`
+	Port int    `env:"PORT" envDefault:"8080"`
+	Skip string `env:"-"`
+	DB   *struct {
+		Host string `env:"HOST"`
+		TLS  struct {
+			Cert string `env:"CERT"`
+		} `envPrefix:"TLS"`
+	} `envPrefix:"DB"`
+	Flat struct {
+		Region string `env:"REGION"`
+	}
+	BatchBase
+	Credentials Credentials
+}
+
+type BatchBase struct {
+	Zone string `env:"ZONE"`
+}
+
+func TestKeys(t *testing.T) {
+	fields := envconfig.Keys[batchConfig]()
+
+	var keys, paths []string
+	for _, f := range fields {
+		keys = append(keys, f.Key)
+		paths = append(paths, f.Path)
+	}
+
+	wantKeys := []string{"NAME", "PORT", "DB_HOST", "DB_TLS_CERT", "REGION", "ZONE"}
+	if !slices.Equal(keys, wantKeys) {
+		t.Errorf("keys:\n got %q\nwant %q", keys, wantKeys)
+	}
+	wantPaths := []string{"Name", "Port", "DB.Host", "DB.TLS.Cert", "Flat.Region", "BatchBase.Zone"}
+	if !slices.Equal(paths, wantPaths) {
+		t.Errorf("paths:\n got %q\nwant %q", paths, wantPaths)
+	}
+
+	if !fields[0].Required() || fields[1].Required() {
+		t.Errorf("unexpected required flags")
+	}
+	if def, ok := fields[1].Default(); !ok || def != "8080" {
+		t.Errorf("expected PORT default 8080, got %q, %v", def, ok)
+	}
+}
+
+func TestReadBatchLookup(t *testing.T) {
+	values := map[string]string{
+		"NAME":         "svc",
+		"DB_HOST":      "db",
+		"CREDS":        "0",
+		"CREDS_0_USER": "user0",
+	}
+
+	var calls [][]string
+	batch := envconfig.BatchLookupFunc(func(ctx context.Context, keys []string) (map[string]string, error) {
+		calls = append(calls, keys)
+		out := map[string]string{}
+		for _, k := range keys {
+			if v, ok := values[k]; ok {
+				out[k] = v
+			}
+		}
+		return out, nil
+	})
+
+	var cfg batchConfig
+	if err := envconfig.ReadWith(&cfg, envconfig.WithBatchLookup(batch)); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "svc" || cfg.Port != 8080 || cfg.DB == nil || cfg.DB.Host != "db" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if len(cfg.Credentials) != 1 || cfg.Credentials[0].User != "user0" {
+		t.Errorf("unexpected credentials %+v", cfg.Credentials)
+	}
+
+	wantCalls := [][]string{
+		{"NAME", "PORT", "DB_HOST", "DB_TLS_CERT", "REGION", "ZONE"},
+		{"CREDS"},
+		{"CREDS_0_USER"},
+		{"CREDS_0_PASS"},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("calls:\n got %q\nwant %q", calls, wantCalls)
+	}
+}
+
+func TestReadBatchLookupError(t *testing.T) {
+	batch := envconfig.BatchLookupFunc(func(ctx context.Context, keys []string) (map[string]string, error) {
+		return nil, errors.New("unavailable")
+	})
+
+	var cfg batchConfig
+	err := envconfig.ReadWith(&cfg, envconfig.WithBatchLookup(batch))
+	assertErr(t, err, "envconfig: batch lookup: unavailable")
+}
+
+func TestParallelLookup(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	lookup := func(ctx context.Context, key string) (string, bool, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if key == "MISSING" {
+			return "", false, nil
+		}
+		return "v-" + key, true, nil
+	}
+
+	keys := []string{"A", "B", "C", "D", "E", "F", "MISSING"}
+	values, err := envconfig.ParallelLookup(lookup, 3).LookupMany(context.Background(), keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(values) != 6 || values["F"] != "v-F" {
+		t.Errorf("unexpected values %v", values)
+	}
+	if _, ok := values["MISSING"]; ok {
+		t.Errorf("expected MISSING to be absent")
+	}
+	if m := maxInFlight.Load(); m > 3 || m < 2 {
+		t.Errorf("expected up to 3 parallel lookups, got %d", m)
+	}
+}
+
+func TestParallelLookupError(t *testing.T) {
+	lookup := func(ctx context.Context, key string) (string, bool, error) {
+		if key == "BAD" {
+			return "", false, errors.New("denied")
+		}
+		return "v", true, nil
+	}
+
+	var cfg struct {
+		Good string `env:"GOOD"`
+		Bad  string `env:"BAD"`
+	}
+	err := envconfig.ReadWith(&cfg, envconfig.WithBatchLookup(envconfig.ParallelLookup(lookup, 4)))
+	assertErr(t, err, `envconfig: batch lookup: envconfig: lookup "BAD": denied`)
+}
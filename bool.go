@@ -0,0 +1,27 @@
+package envconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	lenientTrue  = []string{"1", "t", "true", "y", "yes", "on", "enable", "enabled"}
+	lenientFalse = []string{"0", "f", "false", "n", "no", "off", "disable", "disabled"}
+)
+
+// parseLenientBool parses the spellings in lenientTrue and lenientFalse, ignoring case.
+func parseLenientBool(value string) (bool, error) {
+	for _, v := range lenientTrue {
+		if strings.EqualFold(value, v) {
+			return true, nil
+		}
+	}
+	for _, v := range lenientFalse {
+		if strings.EqualFold(value, v) {
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("invalid boolean %q, accepted values: %s / %s",
+		value, strings.Join(lenientTrue, ", "), strings.Join(lenientFalse, ", "))
+}
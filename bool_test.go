@@ -0,0 +1,85 @@
+package envconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadLenientBool(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "YES":
+			return "Yes", true
+		case "OFF":
+			return "OFF", true
+		case "ENABLED":
+			return "enabled", true
+		case "TRUE":
+			return "true", true
+		case "FLAGS":
+			return "on,no,1", true
+		}
+		return "", false
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		var cfg struct {
+			Yes     bool   `env:"YES" envBool:"lenient"`
+			Off     *bool  `env:"OFF" envBool:"lenient"`
+			Enabled bool   `env:"ENABLED" envBool:"lenient"`
+			True    bool   `env:"TRUE" envBool:"lenient"`
+			Flags   []bool `env:"FLAGS" envBool:"lenient"`
+		}
+		if err := envconfig.Read(&cfg, le); err != nil {
+			t.Fatal(err)
+		}
+
+		if !cfg.Yes || cfg.Off == nil || *cfg.Off || !cfg.Enabled || !cfg.True {
+			t.Errorf("unexpected values: %+v", cfg)
+		}
+		if len(cfg.Flags) != 3 || !cfg.Flags[0] || cfg.Flags[1] || !cfg.Flags[2] {
+			t.Errorf("Flags: unexpected %v", cfg.Flags)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		var cfg struct {
+			Yes bool `env:"YES"`
+			Off bool `env:"OFF"`
+		}
+		if err := envconfig.ReadWith(&cfg, envconfig.WithLookup(le), envconfig.WithLenientBool()); err != nil {
+			t.Fatal(err)
+		}
+		if !cfg.Yes || cfg.Off {
+			t.Errorf("unexpected values: %+v", cfg)
+		}
+
+		var strict struct {
+			Yes bool `env:"YES" envBool:"strict"`
+		}
+		err := envconfig.ReadWith(&strict, envconfig.WithLookup(le), envconfig.WithLenientBool())
+		if err == nil || !strings.Contains(err.Error(), "invalid syntax") {
+			t.Fatalf("expected strict parsing error, got %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		le := func(key string) (string, bool) {
+			return "maybe", true
+		}
+
+		var cfg struct {
+			V bool `env:"V" envBool:"lenient"`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "V" failed to populate: invalid boolean "maybe", accepted values: 1, t, true, y, yes, on, enable, enabled / 0, f, false, n, no, off, disable, disabled`)
+
+		var invalidTag struct {
+			V bool `env:"V" envBool:"loose"`
+		}
+		err = envconfig.Read(&invalidTag, le)
+		assertErr(t, err, `envconfig: field "V": invalid "envBool" tag "loose", expected "lenient" or "strict"`)
+	})
+}
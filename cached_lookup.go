@@ -0,0 +1,123 @@
+package envconfig
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LookupCache memoises the results of a lookup, both found and missing keys, for a TTL.
+// It is safe for concurrent use. Pass its Lookup (or LookupContext) method to Read.
+//
+// Usage:
+//
+//	cache := envconfig.CachedLookup(secrets.Lookup, 5*time.Minute)
+//	err := envconfig.Read(&cfg, cache.Lookup)
+type LookupCache struct {
+	lookup LookupEnvContext
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	gen     uint64 // incremented by Invalidate, fetches started before are not stored
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+type cacheEntry struct {
+	value   string
+	ok      bool
+	expires time.Time
+}
+
+// CacheStats reports how lookups were served by a LookupCache.
+type CacheStats struct {
+	// Hits is the number of lookups served from the cache.
+	Hits uint64
+	// Misses is the number of lookups that called the underlying lookup.
+	Misses uint64
+}
+
+// CachedLookup wraps lookup with a cache. A ttl <= 0 caches results until invalidated.
+func CachedLookup(lookup LookupEnv, ttl time.Duration) *LookupCache {
+	return CachedLookupContext(adaptLookup(lookup), ttl)
+}
+
+// CachedLookupContext wraps a context-aware lookup with a cache. Errors are not cached.
+// A ttl <= 0 caches results until invalidated.
+func CachedLookupContext(lookup LookupEnvContext, ttl time.Duration) *LookupCache {
+	return &LookupCache{
+		lookup:  lookup,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// Lookup resolves key from the cache, calling the underlying lookup when missing or expired.
+// Errors of a context-aware lookup are reported as missing keys, use LookupContext to receive them.
+func (c *LookupCache) Lookup(key string) (string, bool) {
+	v, ok, err := c.LookupContext(context.Background(), key)
+	if err != nil {
+		return "", false
+	}
+	return v, ok
+}
+
+// LookupContext resolves key from the cache, calling the underlying lookup when missing or expired.
+func (c *LookupCache) LookupContext(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	c.mu.Unlock()
+
+	if found && (c.ttl <= 0 || c.now().Before(entry.expires)) {
+		c.hits.Add(1)
+		return entry.value, entry.ok, nil
+	}
+
+	c.mu.Lock()
+	gen := c.gen
+	c.mu.Unlock()
+
+	c.misses.Add(1)
+	v, ok, err := c.lookup(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+
+	c.mu.Lock()
+	if c.gen == gen {
+		c.entries[key] = cacheEntry{value: v, ok: ok, expires: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+
+	return v, ok, nil
+}
+
+// Invalidate drops the given keys from the cache, or all keys if none are given.
+// Results of lookups in flight when Invalidate is called are not cached.
+func (c *LookupCache) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+
+	if len(keys) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// Stats returns the hit and miss counters.
+func (c *LookupCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
@@ -0,0 +1,162 @@
+package envconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCachedLookup(t *testing.T) {
+	calls := map[string]int{}
+	source := func(key string) (string, bool) {
+		calls[key]++
+		if key == "PORT" {
+			return "8080", true
+		}
+		return "", false
+	}
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := CachedLookup(source, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	for range 3 {
+		if v, ok := cache.Lookup("PORT"); !ok || v != "8080" {
+			t.Fatalf("expected PORT=8080, got %q, %v", v, ok)
+		}
+		if _, ok := cache.Lookup("MISSING"); ok {
+			t.Fatal("expected MISSING to not exist")
+		}
+	}
+	if calls["PORT"] != 1 || calls["MISSING"] != 1 {
+		t.Errorf("expected hits and misses to be cached, got calls %v", calls)
+	}
+	if stats := cache.Stats(); stats != (CacheStats{Hits: 4, Misses: 2}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	now = now.Add(time.Minute)
+	cache.Lookup("PORT")
+	if calls["PORT"] != 2 {
+		t.Errorf("expected expired entry to be fetched again, got %d calls", calls["PORT"])
+	}
+
+	cache.Invalidate("MISSING")
+	cache.Lookup("MISSING")
+	cache.Lookup("PORT")
+	if calls["MISSING"] != 2 || calls["PORT"] != 2 {
+		t.Errorf("expected only MISSING to be invalidated, got calls %v", calls)
+	}
+
+	cache.Invalidate()
+	cache.Lookup("MISSING")
+	cache.Lookup("PORT")
+	if calls["MISSING"] != 3 || calls["PORT"] != 3 {
+		t.Errorf("expected all keys to be invalidated, got calls %v", calls)
+	}
+}
+
+func TestCachedLookupNoTTL(t *testing.T) {
+	calls := 0
+	cache := CachedLookup(func(string) (string, bool) {
+		calls++
+		return "v", true
+	}, 0)
+
+	cache.Lookup("K")
+	cache.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+	cache.Lookup("K")
+	if calls != 1 {
+		t.Errorf("expected entry to never expire, got %d calls", calls)
+	}
+}
+
+func TestCachedLookupContext(t *testing.T) {
+	fail := true
+	cache := CachedLookupContext(func(ctx context.Context, key string) (string, bool, error) {
+		if fail {
+			return "", false, errors.New("unavailable")
+		}
+		return "secret", true, nil
+	}, time.Minute)
+
+	if _, _, err := cache.LookupContext(context.Background(), "KEY"); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, ok := cache.Lookup("KEY"); ok {
+		t.Fatal("expected error to be reported as missing key")
+	}
+
+	fail = false
+	v, ok, err := cache.LookupContext(context.Background(), "KEY")
+	if err != nil || !ok || v != "secret" {
+		t.Fatalf("expected errors not to be cached, got %q, %v, %v", v, ok, err)
+	}
+}
+
+func TestCachedLookupConcurrent(t *testing.T) {
+	cache := CachedLookup(func(key string) (string, bool) {
+		return key, true
+	}, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for j := range 100 {
+				key := fmt.Sprintf("K%d", (i+j)%10)
+				if v, _ := cache.Lookup(key); v != key {
+					t.Errorf("expected %q, got %q", key, v)
+				}
+				if j%25 == 0 {
+					cache.Invalidate(key)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if stats := cache.Stats(); stats.Hits+stats.Misses != 800 {
+		t.Errorf("expected 800 lookups, got %+v", stats)
+	}
+}
+
+func TestCachedLookupInvalidateDuringFetch(t *testing.T) {
+	var mu sync.Mutex
+	secret := "old"
+	fetching, release := make(chan struct{}), make(chan struct{})
+	cache := CachedLookup(func(key string) (string, bool) {
+		mu.Lock()
+		v := secret
+		mu.Unlock()
+		if v == "old" {
+			close(fetching)
+			<-release
+		}
+		return v, true
+	}, 0)
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		if v, _ := cache.Lookup("SECRET"); v != "old" {
+			t.Errorf("expected the in-flight lookup to return %q, got %q", "old", v)
+		}
+	})
+
+	<-fetching
+	mu.Lock()
+	secret = "rotated"
+	mu.Unlock()
+	cache.Invalidate("SECRET")
+	close(release)
+	wg.Wait()
+
+	if v, _ := cache.Lookup("SECRET"); v != "rotated" {
+		t.Errorf("expected the stale fetch not to be cached, got %q", v)
+	}
+	if stats := cache.Stats(); stats != (CacheStats{Misses: 2}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/constant"
 	"go/types"
 	"os"
 	"reflect"
@@ -16,6 +18,7 @@ type fieldEntry struct {
 	Key   string
 	Group string
 	Tag   string
+	Type  types.Type
 }
 
 func main() {
@@ -81,7 +84,7 @@ func main() {
 	visiting := map[types.Type]struct{}{named: {}}
 	fields := flattenStruct(st, visiting, "", "", pkg.Types)
 
-	printEnv(fields)
+	printEnv(fields, syntaxIndex(pkgs))
 }
 
 func flattenStruct(
@@ -128,6 +131,7 @@ func flattenStruct(
 			Key:   joinNonEmpty(prefix, fieldEnv, "_"),
 			Group: group,
 			Tag:   tag,
+			Type:  field.Type(),
 		})
 	}
 
@@ -153,7 +157,7 @@ func deref(t types.Type) types.Type {
 	return t
 }
 
-func printEnv(fields []fieldEntry) {
+func printEnv(fields []fieldEntry, syntax map[string]*packages.Package) {
 	slices.SortStableFunc(fields, func(i, j fieldEntry) int {
 		return cmp.Or(strings.Compare(i.Group, j.Group), strings.Compare(i.Key, j.Key))
 	})
@@ -173,10 +177,101 @@ func printEnv(fields []fieldEntry) {
 			lastGroup = f.Group
 		}
 
+		if enum := reflect.StructTag(f.Tag).Get("envEnum"); enum != "" {
+			fmt.Printf("# one of: %s\n", strings.Join(splitEnum(enum), ", "))
+		} else if values, named := enumValues(f.Type, syntax); named != nil {
+			if values != nil {
+				fmt.Printf("# one of: %s\n", strings.Join(values, ", "))
+			} else {
+				fmt.Printf("# one of: see %s.Values\n", named.Obj().Name())
+			}
+		}
 		fmt.Printf("%s=%s\n", f.Key, reflect.StructTag(f.Tag).Get("envDefault"))
 	}
 }
 
+// syntaxIndex returns the loaded packages, including dependencies, by import path.
+func syntaxIndex(pkgs []*packages.Package) map[string]*packages.Package {
+	index := make(map[string]*packages.Package)
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		index[p.PkgPath] = p
+	})
+	return index
+}
+
+// enumValues returns the named type implementing envconfig.Enum for a field of type t
+// (or of its elements) and, if its Values method returns a []string literal of constants,
+// those values.
+func enumValues(t types.Type, syntax map[string]*packages.Package) ([]string, *types.Named) {
+	t = deref(t)
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		t = deref(u.Elem())
+	case *types.Array:
+		t = deref(u.Elem())
+	case *types.Map:
+		t = deref(u.Elem())
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(named), true, named.Obj().Pkg(), "Values")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 ||
+		!types.Identical(sig.Results().At(0).Type(), types.NewSlice(types.Typ[types.String])) {
+		return nil, nil
+	}
+
+	pkg := syntax[fn.Pkg().Path()]
+	if pkg == nil || pkg.TypesInfo == nil {
+		return nil, named
+	}
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || pkg.TypesInfo.Defs[fd.Name] != fn {
+				continue
+			}
+			if fd.Body == nil || len(fd.Body.List) != 1 {
+				return nil, named
+			}
+			ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				return nil, named
+			}
+			lit, ok := ret.Results[0].(*ast.CompositeLit)
+			if !ok {
+				return nil, named
+			}
+
+			var values []string
+			for _, elt := range lit.Elts {
+				tv := pkg.TypesInfo.Types[elt]
+				if tv.Value == nil || tv.Value.Kind() != constant.String {
+					return nil, named
+				}
+				values = append(values, constant.StringVal(tv.Value))
+			}
+			return values, named
+		}
+	}
+	return nil, named
+}
+
+func splitEnum(enum string) []string {
+	values := strings.Split(enum, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return values
+}
+
 func embeddedCommentName(field *types.Var, tag string, rootPkg *types.Package) string {
 	t := deref(field.Type())
 
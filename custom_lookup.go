@@ -7,9 +7,57 @@ import (
 // IgnoreEmptyEnvLookup wraps os.LookupEnv but treats empty values as unset.
 // If the variable is present but "", it returns ok == false.
 func IgnoreEmptyEnvLookup(key string) (string, bool) {
-	v, ok := os.LookupEnv(key)
-	if !ok || v == "" {
+	return IgnoreEmpty(os.LookupEnv)(key)
+}
+
+// IgnoreEmpty wraps lookup treating empty values as unset.
+// If the variable is present but "", it returns ok == false.
+func IgnoreEmpty(lookup LookupEnv) LookupEnv {
+	return func(key string) (string, bool) {
+		v, ok := lookup(key)
+		if !ok || v == "" {
+			return "", false
+		}
+		return v, true
+	}
+}
+
+// Chain returns a lookup querying lookups in order, the first one that has the key wins.
+//
+// Usage:
+//
+//	envconfig.Chain(os.LookupEnv, envconfig.EnvFileLookup(".env"), secrets.Lookup)
+func Chain(lookups ...LookupEnv) LookupEnv {
+	return func(key string) (string, bool) {
+		for _, lookup := range lookups {
+			if v, ok := lookup(key); ok {
+				return v, true
+			}
+		}
 		return "", false
 	}
-	return v, true
+}
+
+// MapLookup returns a lookup reading from m. The map is not copied.
+// Useful for tests and overrides, e.g. Chain(MapLookup(overrides), os.LookupEnv).
+func MapLookup(m map[string]string) LookupEnv {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+// WithKeyPrefix returns a lookup that prepends prefix to every key, e.g. with "APP_" a PORT field reads APP_PORT.
+func WithKeyPrefix(lookup LookupEnv, prefix string) LookupEnv {
+	return func(key string) (string, bool) {
+		return lookup(prefix + key)
+	}
+}
+
+// WithKeyMapping returns a lookup that resolves mapKey(key) instead of key,
+// e.g. strings.ToLower for sources with lower-case keys.
+func WithKeyMapping(lookup LookupEnv, mapKey func(string) string) LookupEnv {
+	return func(key string) (string, bool) {
+		return lookup(mapKey(key))
+	}
 }
@@ -2,6 +2,7 @@ package envconfig
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +35,71 @@ func TestCustomLookup(t *testing.T) {
 		}
 	})
 }
+
+func TestLookupCombinators(t *testing.T) {
+	base := MapLookup(map[string]string{
+		"PORT":     "8080",
+		"EMPTY":    "",
+		"APP_PORT": "9090",
+		"app.host": "localhost",
+	})
+
+	tests := []struct {
+		name   string
+		lookup LookupEnv
+		key    string
+		want   string
+		exists bool
+	}{
+		{name: "map_hit", lookup: base, key: "PORT", want: "8080", exists: true},
+		{name: "map_miss", lookup: base, key: "MISSING"},
+		{name: "map_empty", lookup: base, key: "EMPTY", want: "", exists: true},
+		{name: "ignore_empty", lookup: IgnoreEmpty(base), key: "EMPTY"},
+		{name: "ignore_empty_hit", lookup: IgnoreEmpty(base), key: "PORT", want: "8080", exists: true},
+		{name: "prefix", lookup: WithKeyPrefix(base, "APP_"), key: "PORT", want: "9090", exists: true},
+		{name: "prefix_miss", lookup: WithKeyPrefix(base, "APP_"), key: "EMPTY"},
+		{
+			name: "mapping",
+			lookup: WithKeyMapping(base, func(key string) string {
+				return "app." + strings.ToLower(key)
+			}),
+			key:    "HOST",
+			want:   "localhost",
+			exists: true,
+		},
+		{
+			name:   "chain_first_wins",
+			lookup: Chain(MapLookup(map[string]string{"PORT": "1"}), base),
+			key:    "PORT",
+			want:   "1",
+			exists: true,
+		},
+		{
+			name:   "chain_falls_through",
+			lookup: Chain(MapLookup(nil), base),
+			key:    "PORT",
+			want:   "8080",
+			exists: true,
+		},
+		{
+			name:   "chain_empty_is_a_hit",
+			lookup: Chain(base, MapLookup(map[string]string{"EMPTY": "x"})),
+			key:    "EMPTY",
+			want:   "",
+			exists: true,
+		},
+		{name: "chain_none", lookup: Chain(), key: "PORT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := tt.lookup(tt.key)
+			if ok != tt.exists {
+				t.Fatalf("expected exists=%v, got %v", tt.exists, ok)
+			}
+			if v != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, v)
+			}
+		})
+	}
+}
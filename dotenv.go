@@ -1,103 +1,557 @@
 package envconfig
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strings"
 )
 
+// defaultMaxLineSize is the default limit for a single line of a .env file.
+const defaultMaxLineSize = 1024 * 1024
+
+var (
+	// ErrInvalidKey is reported for keys that don't match [A-Za-z_][A-Za-z0-9_.-]*.
+	ErrInvalidKey = errors.New("invalid key")
+	// ErrUnterminatedQuote is reported for quoted values without a closing quote.
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	// ErrLineTooLong is reported for lines longer than the MaxLineSize limit.
+	ErrLineTooLong = errors.New("line too long")
+)
+
+// ParseError is a syntax error in .env content.
+type ParseError struct {
+	File   string // file name, empty for ParseDotEnv
+	Line   int    // 1-based line
+	Column int    // 1-based byte column
+	Err    error  // ErrInvalidKey, ErrUnterminatedQuote, ErrLineTooLong or another syntax error
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("envconfig: line %d, column %d: %v", e.Line, e.Column, e.Err)
+	}
+	return fmt.Sprintf("envconfig: %s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// DotEnvOption configures ParseDotEnv and LoadEnvFile.
+type DotEnvOption func(*dotenvOptions)
+
+type dotenvOptions struct {
+	allowMissing    bool
+	maxLineSize     int
+	noInterpolation bool
+	fileFirst       bool
+	fileOverridesOS bool
+	fileOnly        bool
+	searchRoot      string
+
+	// layers holds the values of lower-precedence files for interpolation, see EnvFilesLookup.
+	layers map[string]string
+}
+
+func newDotEnvOptions(opts []DotEnvOption) *dotenvOptions {
+	o := &dotenvOptions{maxLineSize: defaultMaxLineSize}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// newVerbatimOptions is newDotEnvOptions with interpolation disabled unless enabled by Interpolate.
+func newVerbatimOptions(opts []DotEnvOption) *dotenvOptions {
+	return newDotEnvOptions(append([]DotEnvOption{DisableInterpolation()}, opts...))
+}
+
+// AllowMissingFile makes LoadEnvFile treat a missing file as empty instead of returning an error.
+func AllowMissingFile() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.allowMissing = true
+	}
+}
+
+// DisableInterpolation keeps ${VAR}, $VAR and ${VAR:-default} references in values verbatim.
+// This is the default of EnvFileLookup and EnvFSLookup, see Interpolate.
+//
+// By default references in unquoted and double-quoted values are replaced, single- and
+// backtick-quoted values are taken literally, and "$$" (or "\$" in double quotes) is a literal "$".
+// A reference resolves to the OS environment, or else to the closest definition of the key
+// earlier in the file (see InterpolateFileFirst). Keys defined later in the file are not visible,
+// so references can't form cycles, and PATH=$PATH:/x extends the OS value.
+// Unset references are empty, ${VAR:-default} uses the default if VAR is unset or empty,
+// ${VAR-default} only if VAR is unset.
+// Values that can't be interpolated, e.g. with an unterminated "${", keep their raw text;
+// LoadEnvFile and ParseDotEnv also report them as errors.
+func DisableInterpolation() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.noInterpolation = true
+	}
+}
+
+// Interpolate enables interpolation for EnvFileLookup and EnvFSLookup, which keep values
+// verbatim by default for compatibility. See DisableInterpolation for the rules.
+func Interpolate() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.noInterpolation = false
+	}
+}
+
+// InterpolateFileFirst makes interpolation prefer earlier definitions in the file over the OS environment.
+// It is implied by FileOverridesOS.
+func InterpolateFileFirst() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.fileFirst = true
+	}
+}
+
+// FileOverridesOS makes values of the file win over the OS environment, e.g. for a checked-out
+// .env file in local development that should override stale shell exports.
+// Interpolation prefers the file as well, see InterpolateFileFirst.
+func FileOverridesOS() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.fileOverridesOS = true
+		o.fileFirst = true
+	}
+}
+
+// FileOnly returns only values of the file, without consulting the OS environment,
+// neither for lookups nor for interpolation. It is meant for hermetic tests.
+func FileOnly() DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.fileOnly = true
+	}
+}
+
+// SearchRoot makes DiscoverEnvFile search up to dir, instead of stopping at the first
+// directory containing go.mod or .git.
+func SearchRoot(dir string) DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.searchRoot = dir
+	}
+}
+
+// MaxLineSize sets the maximum length of a line in bytes (1 MiB by default).
+// Longer lines are reported as ErrLineTooLong. Zero disables the limit.
+func MaxLineSize(n int) DotEnvOption {
+	return func(o *dotenvOptions) {
+		o.maxLineSize = n
+	}
+}
+
 // EnvFileLookup returns a lookup function that reads environment variables
 // from a .env file. It falls back to OS env if a file is not found.
 // The .env file should have lines in the format KEY=VALUE.
 // Comments starting with # are ignored.
 // Empty lines are ignored.
 // Notes:
-//   - If both the .env file and OS environment define a key, the OS environment value wins.
+//   - If both the .env file and OS environment define a key, the OS environment value wins,
+//     unless FileOverridesOS is used. With FileOnly the OS environment is ignored.
 //   - Lines like `export KEY=VALUE` are supported.
-func EnvFileLookup(filePath string) func(string) (string, bool) {
-	envMap := make(map[string]string)
+//   - Double-quoted values may span multiple lines and support the \n, \r, \t, \", \\ and \$ escapes.
+//   - Single- and backtick-quoted values may span multiple lines and are taken literally.
+//   - Unquoted values end at the end of the line or at a # preceded by whitespace.
+//   - Values are taken verbatim, including "$". With Interpolate, ${VAR}, $VAR and ${VAR:-default}
+//     in unquoted and double-quoted values are interpolated, see DisableInterpolation for the rules.
+//   - Malformed lines are skipped.
+func EnvFileLookup(filePath string, opts ...DotEnvOption) func(string) (string, bool) {
+	data, err := os.ReadFile(filePath)
+	return dotenvFileLookup(data, err, newVerbatimOptions(opts))
+}
 
-	file, err := os.Open(filePath)
+// EnvFSLookup is like EnvFileLookup, but reads the file name from fsys, e.g. defaults
+// embedded with go:embed or a fstest.MapFS in tests.
+//
+// Usage:
+//
+//	//go:embed defaults.env
+//	var defaults embed.FS
+//
+//	err := envconfig.Read(&cfg, envconfig.EnvFSLookup(defaults, "defaults.env"))
+//
+// For layered files in a fs.FS, see EnvFilesOptions.FS.
+func EnvFSLookup(fsys fs.FS, name string, opts ...DotEnvOption) LookupEnv {
+	data, err := fs.ReadFile(fsys, name)
+	return dotenvFileLookup(data, err, newVerbatimOptions(opts))
+}
+
+// dotenvFileLookup returns a lookup of the .env content data, ignoring a read error err
+// and malformed lines.
+func dotenvFileLookup(data []byte, err error, o *dotenvOptions) LookupEnv {
+	envMap := make(map[string]string)
 	if err == nil {
-		defer func(file *os.File) {
-			if err := file.Close(); err != nil {
-				_, _ = fmt.Fprintf(os.Stderr, "envconfig: failed to close %q file: %v", filePath, err)
-			}
-		}(file)
-
-		scanner := bufio.NewScanner(file)
-		const (
-			initialBufSize = 64 * 1024
-			maxBufSize     = 1024 * 1024
-		)
-		scanner.Buffer(make([]byte, 0, initialBufSize), maxBufSize)
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-
-			// Skip empty lines and comments
-			if line == "" || strings.HasPrefix(line, "#") {
-				continue
-			}
+		entries, _ := parseDotEnv(string(data), o)
+		for _, e := range entries {
+			envMap[e.key] = e.value
+		}
+	}
+	return o.lookup(envMap)
+}
 
-			if after, ok := strings.CutPrefix(line, "export "); ok {
-				line = strings.TrimSpace(after)
-			}
+// ParseDotEnv parses .env content from r, see EnvFileLookup for the syntax.
+// Malformed lines are reported as *ParseError, joined with errors.Join; the returned map
+// holds the entries parsed successfully, with their raw value if they can't be interpolated.
+// Later entries override earlier ones.
+func ParseDotEnv(r io.Reader, opts ...DotEnvOption) (map[string]string, error) {
+	o := newDotEnvOptions(opts)
 
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) != 2 {
-				continue
-			}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: reading .env: %w", err)
+	}
+	return parseDotEnvMap(string(data), "", o)
+}
 
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
+// LoadEnvFile parses the .env file at path and returns a lookup function with the same
+// semantics as EnvFileLookup: the OS environment wins over the file, unless FileOverridesOS
+// or FileOnly is used.
+// Unlike EnvFileLookup, errors are returned: a missing file (matching fs.ErrNotExist,
+// unless AllowMissingFile is used), read errors, and malformed lines as *ParseError
+// with the file name, line and column.
+func LoadEnvFile(path string, opts ...DotEnvOption) (LookupEnv, error) {
+	o := newDotEnvOptions(opts)
 
-			quoted := false
-			if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
-				q := value[0] // " or '
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && o.allowMissing {
+		return o.lookup(nil), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: %w", err)
+	}
 
-				if idx := strings.LastIndexByte(value, q); idx > 0 {
-					inner := value[1:idx]
-					rest := strings.TrimSpace(value[idx+1:])
+	envMap, err := parseDotEnvMap(string(data), path, o)
+	if err != nil {
+		return nil, err
+	}
+	return o.lookup(envMap), nil
+}
 
-					// If rest starts with a comment, ignore it entirely.
-					if rest == "" || strings.HasPrefix(rest, "#") {
-						value = inner
-						quoted = true
-					}
-					// If the rest doesn't start with #, we fall through to unquoted handling,
-				}
-			}
+// parseDotEnvMap parses src into a map, setting file on parse errors.
+func parseDotEnvMap(src, file string, o *dotenvOptions) (map[string]string, error) {
+	entries, parseErrs := parseDotEnv(src, o)
 
-			if !quoted {
-				// Remove inline comment for unquoted values: value [space]# comment
-				// We do not strip # without prior whitespace to allow "value#partofvalue".
-				if idx := strings.Index(value, "#"); idx >= 0 {
-					// Only consider as comment if there's whitespace before the '#'
-					// e.g., "value # comment", not "value#partofvalue"
-					spaceIdx := strings.LastIndexAny(value[:idx], " \t")
-					if spaceIdx >= len(value[:idx])-1 {
-						value = strings.TrimSpace(value[:idx])
-					}
-				}
-			}
+	envMap := make(map[string]string, len(entries))
+	for _, e := range entries {
+		envMap[e.key] = e.value
+	}
+
+	return envMap, joinParseErrors(parseErrs, file)
+}
+
+// joinParseErrors sets file on errs and joins them.
+func joinParseErrors(errs []*ParseError, file string) error {
+	joined := make([]error, len(errs))
+	for i, err := range errs {
+		err.File = file
+		joined[i] = err
+	}
+	return errors.Join(joined...)
+}
+
+// lookup returns a lookup function combining envMap and the OS environment
+// according to the precedence options.
+func (o *dotenvOptions) lookup(envMap map[string]string) LookupEnv {
+	switch {
+	case o.fileOnly:
+		return MapLookup(envMap)
+	case o.fileOverridesOS:
+		return Chain(MapLookup(envMap), os.LookupEnv)
+	}
+	return Chain(os.LookupEnv, MapLookup(envMap))
+}
 
-			envMap[key] = value
+// lookupOS returns the OS environment lookup used for interpolation, nil with FileOnly.
+func (o *dotenvOptions) lookupOS() LookupEnv {
+	if o.fileOnly {
+		return nil
+	}
+	return os.LookupEnv
+}
+
+// dotenvEntry is a single KEY=VALUE assignment of a .env file.
+type dotenvEntry struct {
+	key    string
+	value  string
+	quote  byte // quote character of the value, 0 if unquoted
+	export bool
+
+	// escapedDollars holds the offsets in value of "$" written as "\$", excluded from interpolation.
+	escapedDollars []int
+
+	line, col  int // 1-based position of the assignment
+	start, end int // byte offsets of the assignment, excluding the line break
+
+	valueStart, valueEnd int // byte offsets of the raw value, including quotes
+}
+
+// parseDotEnv parses .env content and interpolates its values, unless disabled in o.
+// Entries with syntax errors are skipped and reported, parsing continues on the next line.
+func parseDotEnv(src string, o *dotenvOptions) ([]dotenvEntry, []*ParseError) {
+	p := &dotenvParser{src: src, line: 1, maxLineSize: o.maxLineSize}
+	if strings.HasPrefix(src, "\uFEFF") {
+		p.pos = len("\uFEFF")
+		p.lineStart = p.pos
+	}
+
+	var (
+		entries []dotenvEntry
+		errs    []*ParseError
+	)
+	for p.pos < len(p.src) {
+		e, err := p.parseLine()
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
-		if err := scanner.Err(); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "envconfig: error file scanning %q: %v", filePath, err)
+		if e != nil {
+			entries = append(entries, *e)
 		}
 	}
 
-	return func(key string) (string, bool) {
-		if value, exists := os.LookupEnv(key); exists {
-			return value, true
+	if !o.noInterpolation {
+		var interpolationErrs []*ParseError
+		entries, interpolationErrs = interpolateDotEnv(entries, o)
+		errs = append(errs, interpolationErrs...)
+	}
+	return entries, errs
+}
+
+type dotenvParser struct {
+	src         string
+	pos         int
+	line        int
+	lineStart   int // offset of the first byte of the current line
+	maxLineSize int
+}
+
+// errorf returns a ParseError at pos on the current line.
+func (p *dotenvParser) errorf(pos int, format string, args ...any) *ParseError {
+	return &ParseError{Line: p.line, Column: pos - p.lineStart + 1, Err: fmt.Errorf(format, args...)}
+}
+
+// lineTooLong reports whether the current line exceeds maxLineSize.
+func (p *dotenvParser) lineTooLong() bool {
+	if p.maxLineSize <= 0 {
+		return false
+	}
+	n := strings.IndexByte(p.src[p.lineStart:], '\n')
+	if n < 0 {
+		n = len(p.src) - p.lineStart
+	}
+	if n > 0 && p.src[p.lineStart+n-1] == '\r' {
+		n--
+	}
+	return n > p.maxLineSize
+}
+
+// peek returns the current byte, or 0 at the end of input.
+func (p *dotenvParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for isBlank(p.peek()) {
+		p.pos++
+	}
+}
+
+// atLineEnd reports whether the parser is at a line break or the end of input.
+func (p *dotenvParser) atLineEnd() bool {
+	rest := p.src[p.pos:]
+	return rest == "" || rest[0] == '\n' || strings.HasPrefix(rest, "\r\n")
+}
+
+// newline advances past the line break at pos.
+func (p *dotenvParser) newline() {
+	if p.peek() == '\r' {
+		p.pos++
+	}
+	p.pos++
+	p.line++
+	p.lineStart = p.pos
+}
+
+// skipLine advances to the beginning of the next line.
+func (p *dotenvParser) skipLine() {
+	for !p.atLineEnd() {
+		p.pos++
+	}
+	if p.pos < len(p.src) {
+		p.newline()
+	}
+}
+
+// parseLine parses one assignment, which may span multiple lines if its value is quoted.
+// It returns nil for blank and comment lines.
+func (p *dotenvParser) parseLine() (*dotenvEntry, *ParseError) {
+	if p.lineTooLong() {
+		err := p.errorf(p.lineStart+p.maxLineSize, "%w: more than %d bytes", ErrLineTooLong, p.maxLineSize)
+		p.skipLine()
+		return nil, err
+	}
+
+	p.skipBlanks()
+	if p.atLineEnd() || p.peek() == '#' {
+		p.skipLine()
+		return nil, nil
+	}
+
+	e := &dotenvEntry{line: p.line, col: p.pos - p.lineStart + 1, start: p.pos}
+	if rest, ok := strings.CutPrefix(p.src[p.pos:], "export"); ok && rest != "" && isBlank(rest[0]) {
+		e.export = true
+		p.pos += len("export")
+		p.skipBlanks()
+	}
+
+	keyStart := p.pos
+	for !p.atLineEnd() && !strings.ContainsRune("= \t#", rune(p.peek())) {
+		p.pos++
+	}
+	e.key = p.src[keyStart:p.pos]
+	p.skipBlanks()
+
+	if e.key == "" {
+		err := p.errorf(keyStart, "missing key")
+		p.skipLine()
+		return nil, err
+	}
+	if p.peek() != '=' {
+		err := p.errorf(p.pos, "expected '=' after key %q", e.key)
+		p.skipLine()
+		return nil, err
+	}
+	if !validEnvKey(e.key) {
+		err := p.errorf(keyStart, "%w %q", ErrInvalidKey, e.key)
+		p.skipLine()
+		return nil, err
+	}
+	p.pos++
+	p.skipBlanks()
+
+	e.valueStart = p.pos
+	switch q := p.peek(); q {
+	case '"', '\'', '`':
+		value, escapedDollars, err := p.parseQuoted()
+		if err != nil {
+			return nil, err
 		}
+		e.value, e.quote, e.escapedDollars = value, q, escapedDollars
+		e.valueEnd = p.pos
 
-		if value, exists := envMap[key]; exists {
-			return value, true
+		p.skipBlanks()
+		if !p.atLineEnd() && p.peek() != '#' {
+			err := p.errorf(p.pos, "unexpected %q after closing quote", p.peek())
+			p.skipLine()
+			return nil, err
+		}
+	default:
+		valueStart := p.pos
+		for !p.atLineEnd() && !(p.peek() == '#' && (p.pos == valueStart || isBlank(p.src[p.pos-1]))) {
+			p.pos++
 		}
+		e.value = strings.TrimRight(p.src[valueStart:p.pos], " \t")
+		e.valueEnd = valueStart + len(e.value)
+	}
 
-		return "", false
+	// Skip an inline comment.
+	for !p.atLineEnd() {
+		p.pos++
+	}
+	e.end = p.pos
+	if p.pos < len(p.src) {
+		p.newline()
 	}
+	return e, nil
+}
+
+// parseQuoted parses a quoted value starting at the opening quote.
+// Line breaks inside the quotes are kept, normalized to \n.
+// On an unterminated quote the rest of the opening line is skipped.
+// It also returns the offsets of escaped dollar signs in the value.
+func (p *dotenvParser) parseQuoted() (string, []int, *ParseError) {
+	q := p.peek()
+	open, openLine, openLineStart := p.pos, p.line, p.lineStart
+	p.pos++
+
+	var (
+		b              strings.Builder
+		escapedDollars []int
+	)
+	for {
+		if p.pos >= len(p.src) {
+			p.pos, p.line, p.lineStart = open, openLine, openLineStart
+			err := p.errorf(open, "%w %c", ErrUnterminatedQuote, q)
+			p.skipLine()
+			return "", nil, err
+		}
+
+		c := p.src[p.pos]
+		switch {
+		case c == q:
+			p.pos++
+			return b.String(), escapedDollars, nil
+		case c == '\n' || c == '\r' && strings.HasPrefix(p.src[p.pos:], "\r\n"):
+			b.WriteByte('\n')
+			p.newline()
+			if p.lineTooLong() {
+				err := p.errorf(p.lineStart+p.maxLineSize, "%w: more than %d bytes", ErrLineTooLong, p.maxLineSize)
+				p.skipLine()
+				return "", nil, err
+			}
+		case c == '\\' && q == '"' && p.pos+1 < len(p.src) && p.src[p.pos+1] != '\n' && p.src[p.pos+1] != '\r':
+			if p.src[p.pos+1] == '$' {
+				escapedDollars = append(escapedDollars, b.Len())
+			}
+			b.WriteString(unescapeDotEnv(p.src[p.pos+1]))
+			p.pos += 2
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// unescapeDotEnv returns the replacement of the escape sequence \c in a double-quoted value.
+// Unknown escape sequences are kept verbatim.
+func unescapeDotEnv(c byte) string {
+	switch c {
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	case '"', '\\', '$':
+		return string(c)
+	}
+	return "\\" + string(c)
+}
+
+// validEnvKey reports whether key is a valid .env key: a letter or underscore
+// followed by letters, digits, underscores, dots or dashes.
+func validEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
 }
@@ -0,0 +1,94 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// AppliedEnv reports the keys ApplyEnvFile set in the process environment.
+type AppliedEnv struct {
+	Set     []string // keys set from the file, in file order
+	Skipped []string // keys left unchanged because they were already set
+
+	previous map[string]*string // values of Set keys before ApplyEnvFile, nil if unset
+}
+
+// ApplyEnvFile parses the .env file at path and sets its entries in the process environment
+// with os.Setenv, for dependencies reading os.Getenv directly. Keys already set in the
+// environment are skipped, unless overwrite is true.
+//
+// Nothing is set if the file can't be read or parsed; errors are reported as for LoadEnvFile.
+// Use Restore to undo the changes, e.g. in a test cleanup:
+//
+//	applied, err := envconfig.ApplyEnvFile("testdata/.env", true)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	t.Cleanup(func() { _ = applied.Restore() })
+func ApplyEnvFile(path string, overwrite bool, opts ...DotEnvOption) (*AppliedEnv, error) {
+	o := newDotEnvOptions(opts)
+	if overwrite {
+		o.fileFirst = true
+	}
+
+	applied := &AppliedEnv{previous: make(map[string]*string)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && o.allowMissing {
+		return applied, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: %w", err)
+	}
+
+	entries, parseErrs := parseDotEnv(string(data), o)
+	if len(parseErrs) > 0 {
+		return nil, joinParseErrors(parseErrs, path)
+	}
+
+	values := make(map[string]string, len(entries))
+	var keys []string
+	for _, e := range entries {
+		if _, ok := values[e.key]; !ok {
+			keys = append(keys, e.key)
+		}
+		values[e.key] = e.value
+	}
+
+	for _, key := range keys {
+		prev, exists := os.LookupEnv(key)
+		if exists && !overwrite {
+			applied.Skipped = append(applied.Skipped, key)
+			continue
+		}
+
+		if err := os.Setenv(key, values[key]); err != nil {
+			return applied, fmt.Errorf("envconfig: setting %q: %w", key, err)
+		}
+		applied.Set = append(applied.Set, key)
+		applied.previous[key] = nil
+		if exists {
+			applied.previous[key] = &prev
+		}
+	}
+	return applied, nil
+}
+
+// Restore resets the keys set by ApplyEnvFile to their previous values, unsetting keys that were not set.
+func (a *AppliedEnv) Restore() error {
+	var errs []error
+	for _, key := range a.Set {
+		var err error
+		if prev := a.previous[key]; prev != nil {
+			err = os.Setenv(key, *prev)
+		} else {
+			err = os.Unsetenv(key)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("envconfig: restoring %q: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package envconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestApplyEnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	content := "APPLY_NEW=file\nAPPLY_EXISTING=file\nAPPLY_REF=${APPLY_EXISTING}\nAPPLY_NEW=file2"
+	if err := os.WriteFile(envFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		overwrite   bool
+		want        map[string]string
+		wantSet     []string
+		wantSkipped []string
+	}{
+		{
+			name:        "keep_existing",
+			want:        map[string]string{"APPLY_NEW": "file2", "APPLY_EXISTING": "os", "APPLY_REF": "os"},
+			wantSet:     []string{"APPLY_NEW", "APPLY_REF"},
+			wantSkipped: []string{"APPLY_EXISTING"},
+		},
+		{
+			name:      "overwrite",
+			overwrite: true,
+			want:      map[string]string{"APPLY_NEW": "file2", "APPLY_EXISTING": "file", "APPLY_REF": "file"},
+			wantSet:   []string{"APPLY_NEW", "APPLY_EXISTING", "APPLY_REF"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("APPLY_EXISTING", "os")
+			t.Setenv("APPLY_NEW", "")
+			t.Setenv("APPLY_REF", "")
+			_ = os.Unsetenv("APPLY_NEW")
+			_ = os.Unsetenv("APPLY_REF")
+
+			applied, err := ApplyEnvFile(envFile, tc.overwrite)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(applied.Set, tc.wantSet) || !slices.Equal(applied.Skipped, tc.wantSkipped) {
+				t.Errorf("expected set %v, skipped %v, got %v, %v", tc.wantSet, tc.wantSkipped, applied.Set, applied.Skipped)
+			}
+			for key, want := range tc.want {
+				if got := os.Getenv(key); got != want {
+					t.Errorf("%s: expected %q, got %q", key, want, got)
+				}
+			}
+
+			if err := applied.Restore(); err != nil {
+				t.Fatal(err)
+			}
+			if got := os.Getenv("APPLY_EXISTING"); got != "os" {
+				t.Errorf("expected APPLY_EXISTING to be restored, got %q", got)
+			}
+			for _, key := range []string{"APPLY_NEW", "APPLY_REF"} {
+				if got, ok := os.LookupEnv(key); ok {
+					t.Errorf("expected %s to be unset, got %q", key, got)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyEnvFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envFile, []byte("APPLY_VALID=1\nAPPLY_BROKEN='x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APPLY_VALID", "")
+	_ = os.Unsetenv("APPLY_VALID")
+
+	_, err := ApplyEnvFile(envFile, false)
+	if !errors.Is(err, ErrUnterminatedQuote) {
+		t.Fatalf("expected ErrUnterminatedQuote, got %v", err)
+	}
+	if _, ok := os.LookupEnv("APPLY_VALID"); ok {
+		t.Error("expected nothing to be set on a parse error")
+	}
+
+	if _, err := ApplyEnvFile(filepath.Join(dir, "missing.env"), false); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	applied, err := ApplyEnvFile(filepath.Join(dir, "missing.env"), false, AllowMissingFile())
+	if err != nil || len(applied.Set) != 0 {
+		t.Errorf("expected an empty result, got %v, %v", applied, err)
+	}
+}
@@ -0,0 +1,79 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// rootMarkers are the entries marking the root of a project for DiscoverEnvFile.
+var rootMarkers = []string{"go.mod", ".git"}
+
+// DiscoverEnvFile searches the file name (e.g. ".env") in the working directory and its parents,
+// and loads the first one found with LoadEnvFile. It returns the lookup along with the
+// path of the file, e.g. for logging.
+//
+// The search stops at the first directory containing go.mod or .git (after checking it),
+// so `go test ./...` in nested packages finds the .env at the module root.
+// Use SearchRoot to search up to another directory instead.
+// If no file is found, the error matches fs.ErrNotExist, unless AllowMissingFile is used.
+func DiscoverEnvFile(name string, opts ...DotEnvOption) (LookupEnv, string, error) {
+	o := newDotEnvOptions(opts)
+
+	path, err := findEnvFile(name, o.searchRoot)
+	if err != nil {
+		if o.allowMissing && errors.Is(err, fs.ErrNotExist) {
+			return o.lookup(nil), "", nil
+		}
+		return nil, "", err
+	}
+
+	lookup, err := LoadEnvFile(path, opts...)
+	if err != nil {
+		return nil, path, err
+	}
+	return lookup, path, nil
+}
+
+// findEnvFile returns the path of name in the working directory or the closest parent,
+// searching up to root, or if empty up to a directory containing a root marker.
+func findEnvFile(name, root string) (string, error) {
+	start, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("envconfig: %w", err)
+	}
+	if root != "" {
+		if root, err = filepath.Abs(root); err != nil {
+			return "", fmt.Errorf("envconfig: %w", err)
+		}
+	}
+
+	dir := start
+	for {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		if root != "" && dir == root || root == "" && hasRootMarker(dir) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return "", fmt.Errorf("envconfig: %s not found from %s up to %s: %w", name, start, dir, fs.ErrNotExist)
+}
+
+func hasRootMarker(dir string) bool {
+	for _, marker := range rootMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
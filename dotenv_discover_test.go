@@ -0,0 +1,74 @@
+package envconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverEnvFile(t *testing.T) {
+	// repo/.env, repo/go.mod, repo/pkg/nested/ and a .env above the module root.
+	base := t.TempDir()
+	repo := filepath.Join(base, "repo")
+	nested := filepath.Join(repo, "pkg", "nested")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(base, ".env"):   "DISCOVER_KEY=outside",
+		filepath.Join(base, ".other"): "DISCOVER_KEY=outside-other",
+		filepath.Join(repo, ".env"):   "DISCOVER_KEY=repo",
+		filepath.Join(repo, "go.mod"): "module example.com/repo",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(nested)
+
+	t.Run("found_at_module_root", func(t *testing.T) {
+		lookup, path, err := DiscoverEnvFile(".env")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != filepath.Join(repo, ".env") {
+			t.Errorf("unexpected path %q", path)
+		}
+		if v, _ := lookup("DISCOVER_KEY"); v != "repo" {
+			t.Errorf("expected repo value, got %q", v)
+		}
+	})
+
+	t.Run("stops_at_module_root", func(t *testing.T) {
+		_, _, err := DiscoverEnvFile(".other")
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected fs.ErrNotExist, got %v", err)
+		}
+
+		lookup, path, err := DiscoverEnvFile(".other", AllowMissingFile())
+		if err != nil || path != "" || lookup == nil {
+			t.Fatalf("expected an empty result, got %q, %v", path, err)
+		}
+	})
+
+	t.Run("search_root", func(t *testing.T) {
+		lookup, path, err := DiscoverEnvFile(".other", SearchRoot(base))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != filepath.Join(base, ".other") {
+			t.Errorf("unexpected path %q", path)
+		}
+		if v, _ := lookup("DISCOVER_KEY"); v != "outside-other" {
+			t.Errorf("expected outside-other, got %q", v)
+		}
+
+		_, _, err = DiscoverEnvFile(".other", SearchRoot(repo))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected fs.ErrNotExist below the search root, got %v", err)
+		}
+	})
+}
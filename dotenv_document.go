@@ -0,0 +1,216 @@
+package envconfig
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Document is an editable .env file. Lines that are not changed with Set or Delete,
+// including comments, blank lines, `export` prefixes, quoting and inline comments,
+// are written back byte for byte.
+//
+// Usage:
+//
+//	doc, err := envconfig.ParseDocument(f)
+//	if err != nil {
+//		return err
+//	}
+//	if err := doc.Set("API_TOKEN", token); err != nil {
+//		return err
+//	}
+//	_, err = doc.WriteTo(out)
+type Document struct {
+	segments []docSegment
+}
+
+// docSegment is a run of lines of a Document: an assignment, or any other text.
+type docSegment struct {
+	text  string // raw text, including the line break
+	key   string // empty for comments and blank lines
+	value string
+	quote byte
+
+	valueStart, valueEnd int // offsets of the raw value in text, including quotes
+}
+
+// ParseDocument parses .env content from r into a Document, see EnvFileLookup for the syntax.
+// Values are not interpolated. Malformed lines are reported as for ParseDotEnv.
+func ParseDocument(r io.Reader) (*Document, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: reading .env: %w", err)
+	}
+	src := string(data)
+
+	entries, parseErrs := parseDotEnv(src, newDotEnvOptions([]DotEnvOption{DisableInterpolation()}))
+	if len(parseErrs) > 0 {
+		return nil, joinParseErrors(parseErrs, "")
+	}
+
+	d := &Document{}
+	pos := 0
+	for _, e := range entries {
+		lineStart := e.start - (e.col - 1)
+		if lineStart > pos {
+			d.segments = append(d.segments, docSegment{text: src[pos:lineStart]})
+		}
+
+		end := e.end
+		if strings.HasPrefix(src[end:], "\r\n") {
+			end += 2
+		} else if strings.HasPrefix(src[end:], "\n") {
+			end++
+		}
+
+		d.segments = append(d.segments, docSegment{
+			text:       src[lineStart:end],
+			key:        e.key,
+			value:      e.value,
+			quote:      e.quote,
+			valueStart: e.valueStart - lineStart,
+			valueEnd:   e.valueEnd - lineStart,
+		})
+		pos = end
+	}
+	if pos < len(src) {
+		d.segments = append(d.segments, docSegment{text: src[pos:]})
+	}
+	return d, nil
+}
+
+// Get returns the value of the last assignment of key, without interpolation.
+func (d *Document) Get(key string) (string, bool) {
+	if i := d.last(key); i >= 0 {
+		return d.segments[i].value, true
+	}
+	return "", false
+}
+
+// Keys returns the assigned keys in order of their first assignment.
+func (d *Document) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, s := range d.segments {
+		if s.key != "" && !seen[s.key] {
+			seen[s.key] = true
+			keys = append(keys, s.key)
+		}
+	}
+	return keys
+}
+
+// Set replaces the value of the last assignment of key, keeping its indentation, `export` prefix,
+// quote style where possible and inline comment, or appends KEY=value if key is not assigned.
+// Values are quoted as needed.
+func (d *Document) Set(key, value string) error {
+	if !validEnvKey(key) {
+		return fmt.Errorf("envconfig: %w %q", ErrInvalidKey, key)
+	}
+
+	i := d.last(key)
+	if i < 0 {
+		if n := len(d.segments); n > 0 && !strings.HasSuffix(d.segments[n-1].text, "\n") {
+			d.segments[n-1].text += lineBreak(d.segments)
+		}
+		raw := encodeDotEnvValue(value, 0)
+		d.segments = append(d.segments, docSegment{
+			text:       key + "=" + raw + lineBreak(d.segments),
+			key:        key,
+			value:      value,
+			quote:      quoteOf(raw),
+			valueStart: len(key) + 1,
+			valueEnd:   len(key) + 1 + len(raw),
+		})
+		return nil
+	}
+
+	s := &d.segments[i]
+	raw := encodeDotEnvValue(value, s.quote)
+	s.text = s.text[:s.valueStart] + raw + s.text[s.valueEnd:]
+	s.value, s.quote, s.valueEnd = value, quoteOf(raw), s.valueStart+len(raw)
+	return nil
+}
+
+// Delete removes all assignments of key and reports whether there were any.
+func (d *Document) Delete(key string) bool {
+	n := len(d.segments)
+	d.segments = slices.DeleteFunc(d.segments, func(s docSegment) bool { return s.key == key })
+	return len(d.segments) != n
+}
+
+// WriteTo writes the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, s := range d.segments {
+		n, err := io.WriteString(w, s.text)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// String returns the document content.
+func (d *Document) String() string {
+	var b strings.Builder
+	_, _ = d.WriteTo(&b)
+	return b.String()
+}
+
+// last returns the index of the last segment assigning key, or -1.
+func (d *Document) last(key string) int {
+	for i := len(d.segments) - 1; i >= 0; i-- {
+		if d.segments[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// lineBreak returns the line break used by segments, "\r\n" or "\n".
+func lineBreak(segments []docSegment) string {
+	for _, s := range segments {
+		if i := strings.IndexByte(s.text, '\n'); i >= 0 {
+			if i > 0 && s.text[i-1] == '\r' {
+				return "\r\n"
+			}
+			return "\n"
+		}
+	}
+	return "\n"
+}
+
+// encodeDotEnvValue returns value as written in a .env file, in the quote style q if it can
+// represent value. Otherwise, value is unquoted if possible, or double-quoted.
+func encodeDotEnvValue(value string, q byte) string {
+	switch q {
+	case '\'', '`':
+		if !strings.ContainsRune(value, rune(q)) {
+			return string(q) + value + string(q)
+		}
+	case '"':
+		return doubleQuote(value)
+	}
+
+	if !strings.ContainsAny(value, " \t\r\n#\"'`\\$") {
+		return value
+	}
+	return doubleQuote(value)
+}
+
+var doubleQuoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`)
+
+func doubleQuote(value string) string {
+	return `"` + doubleQuoteReplacer.Replace(value) + `"`
+}
+
+// quoteOf returns the quote character of a raw value, 0 if unquoted.
+func quoteOf(raw string) byte {
+	if raw != "" && strings.ContainsRune("\"'`", rune(raw[0])) {
+		return raw[0]
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package envconfig
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testDocument = "\uFEFF# Database\r\n" +
+	"export DB_HOST=localhost   # local only\r\n" +
+	"  DB_PASS='s3cr3t' # rotate me\r\n" +
+	"\r\n" +
+	"CERT=\"-----BEGIN-----\r\nMIIB\r\n-----END-----\"\r\n" +
+	"TOKEN=old\r\n" +
+	"TOKEN=older\r\n" +
+	"URL=http://${DB_HOST}"
+
+func TestDocumentRoundTrip(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != testDocument || n != int64(len(testDocument)) {
+		t.Errorf("expected byte-for-byte round trip, got %q (%d bytes)", buf.String(), n)
+	}
+
+	if got, want := doc.Keys(), []string{"DB_HOST", "DB_PASS", "CERT", "TOKEN", "URL"}; !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	for key, want := range map[string]string{
+		"DB_HOST": "localhost",
+		"DB_PASS": "s3cr3t",
+		"CERT":    "-----BEGIN-----\nMIIB\n-----END-----",
+		"TOKEN":   "older",
+		"URL":     "http://${DB_HOST}",
+	} {
+		if got, ok := doc.Get(key); !ok || got != want {
+			t.Errorf("%s: expected %q, got %q (%v)", key, want, got, ok)
+		}
+	}
+	if _, ok := doc.Get("MISSING"); ok {
+		t.Error("expected MISSING to be unset")
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, value := range map[string]string{
+		"DB_HOST": "db.internal",
+		"DB_PASS": "it's new",
+		"TOKEN":   "new",
+		"CERT":    "a\nb",
+		"NEW":     "with space $HOME",
+	} {
+		if err := doc.Set(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !doc.Delete("URL") || doc.Delete("URL") {
+		t.Error("expected URL to be deleted once")
+	}
+
+	want := "\uFEFF# Database\r\n" +
+		"export DB_HOST=db.internal   # local only\r\n" +
+		"  DB_PASS=\"it's new\" # rotate me\r\n" +
+		"\r\n" +
+		"CERT=\"a\\nb\"\r\n" +
+		"TOKEN=old\r\n" +
+		"TOKEN=new\r\n" +
+		"NEW=\"with space \\$HOME\"\r\n"
+	if got := doc.String(); got != want {
+		t.Errorf("unexpected document:\n%q\nwant:\n%q", got, want)
+	}
+
+	// The written document parses back to the new values.
+	reparsed, err := ParseDotEnv(strings.NewReader(doc.String()), DisableInterpolation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"DB_PASS": "it's new", "CERT": "a\nb", "NEW": "with space $HOME"} {
+		if reparsed[key] != value {
+			t.Errorf("%s: expected %q after reparsing, got %q", key, value, reparsed[key])
+		}
+	}
+}
+
+func TestDocumentSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		value   string
+		want    string
+	}{
+		{name: "empty", content: "", key: "A", value: "1", want: "A=1\n"},
+		{name: "no_trailing_newline", content: "A=1", key: "B", value: "2", want: "A=1\nB=2\n"},
+		{name: "keep_single_quotes", content: "A='x' # c\n", key: "A", value: `$y`, want: "A='$y' # c\n"},
+		{name: "keep_backticks", content: "A=`x`\n", key: "A", value: "y", want: "A=`y`\n"},
+		{name: "keep_double_quotes", content: "A=\"x\"\n", key: "A", value: "y", want: "A=\"y\"\n"},
+		{name: "quote_when_needed", content: "A=x\n", key: "A", value: "a#b", want: "A=\"a#b\"\n"},
+		{name: "empty_value", content: "A=x\n", key: "A", value: "", want: "A=\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := ParseDocument(strings.NewReader(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := doc.Set(tc.key, tc.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := doc.String(); got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	if _, err := ParseDocument(strings.NewReader("A='x")); !errors.Is(err, ErrUnterminatedQuote) {
+		t.Errorf("expected ErrUnterminatedQuote, got %v", err)
+	}
+
+	doc, err := ParseDocument(strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Set("1A", "x"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// defaultEnvironmentKey is the key naming the environment for EnvFilesLookup.
+const defaultEnvironmentKey = "APP_ENV"
+
+// EnvFilesOptions configures EnvFilesLookup.
+type EnvFilesOptions struct {
+	// Dir is the directory of the files, the working directory (or the root of FS) if empty.
+	Dir string
+
+	// FS is the file system to read the files from, e.g. an embed.FS.
+	// If nil, the files are read from the OS file system.
+	FS fs.FS
+
+	// Files lists the files to load, lowest precedence first.
+	// Missing files are errors unless AllowMissingFile is set in Options.
+	// If empty, the files are derived from Environment.
+	Files []string
+
+	// Environment selects the files to load, lowest precedence first:
+	//
+	//	.env
+	//	.env.local              (not for the "test" environment)
+	//	.env.<Environment>
+	//	.env.<Environment>.local
+	//
+	// Missing files are skipped. If empty, it is read from the OS environment
+	// variable EnvironmentKey; if that is not set either, only .env and .env.local are loaded.
+	Environment string
+
+	// EnvironmentKey names the OS environment variable holding the environment, "APP_ENV" by default.
+	EnvironmentKey string
+
+	// Options configure parsing of each file.
+	Options []DotEnvOption
+}
+
+// EnvFiles holds the merged values of layered .env files.
+// Pass its Lookup method to Read.
+//
+// Usage:
+//
+//	files, err := envconfig.EnvFilesLookup(envconfig.EnvFilesOptions{Environment: "production"})
+//	err = envconfig.Read(&cfg, files.Lookup)
+type EnvFiles struct {
+	lookup  LookupEnv
+	values  map[string]string
+	sources map[string]string
+	files   []string
+}
+
+// EnvFilesLookup loads layered .env files in the style of dotenv-flow, see EnvFilesOptions.
+// Values of later files override earlier ones, and references in a file can
+// be interpolated with values of earlier files. Like EnvFileLookup, the OS environment
+// wins over all files by default.
+func EnvFilesLookup(opts EnvFilesOptions) (*EnvFiles, error) {
+	o := newDotEnvOptions(opts.Options)
+
+	files, allowMissing := opts.Files, o.allowMissing
+	if len(files) == 0 {
+		files, allowMissing = envFileNames(opts), true
+	}
+
+	f := &EnvFiles{
+		values:  make(map[string]string),
+		sources: make(map[string]string),
+	}
+	for _, name := range files {
+		path, data, err := opts.readFile(name)
+		if errors.Is(err, fs.ErrNotExist) && allowMissing {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("envconfig: %w", err)
+		}
+
+		layer := *o
+		layer.layers = f.values
+		values, err := parseDotEnvMap(string(data), path, &layer)
+		if err != nil {
+			return nil, err
+		}
+
+		for key := range values {
+			f.sources[key] = path
+		}
+		maps.Copy(f.values, values)
+		f.files = append(f.files, path)
+	}
+
+	f.lookup = o.lookup(f.values)
+	return f, nil
+}
+
+// readFile reads the file name relative to Dir, from FS if set, and returns its path.
+func (opts EnvFilesOptions) readFile(name string) (string, []byte, error) {
+	if opts.FS != nil {
+		name = path.Join(opts.Dir, name)
+		data, err := fs.ReadFile(opts.FS, name)
+		return name, data, err
+	}
+
+	if opts.Dir != "" && !filepath.IsAbs(name) {
+		name = filepath.Join(opts.Dir, name)
+	}
+	data, err := os.ReadFile(name)
+	return name, data, err
+}
+
+// envFileNames returns the files to load for the environment of opts, lowest precedence first.
+func envFileNames(opts EnvFilesOptions) []string {
+	env := opts.Environment
+	if env == "" {
+		key := opts.EnvironmentKey
+		if key == "" {
+			key = defaultEnvironmentKey
+		}
+		env = os.Getenv(key)
+	}
+
+	files := []string{".env"}
+	if env != "test" {
+		files = append(files, ".env.local")
+	}
+	if env != "" {
+		files = append(files, ".env."+env, ".env."+env+".local")
+	}
+	return files
+}
+
+// Lookup returns the value of key from the OS environment, or else from the file with
+// the highest precedence defining it. FileOverridesOS and FileOnly in EnvFilesOptions.Options
+// change the precedence of the OS environment as for LoadEnvFile.
+func (f *EnvFiles) Lookup(key string) (string, bool) {
+	return f.lookup(key)
+}
+
+// Source returns the path of the file providing key, ignoring the OS environment.
+func (f *EnvFiles) Source(key string) (string, bool) {
+	path, ok := f.sources[key]
+	return path, ok
+}
+
+// Files returns the paths of the loaded files, lowest precedence first.
+func (f *EnvFiles) Files() []string {
+	return slices.Clone(f.files)
+}
@@ -0,0 +1,182 @@
+package envconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func writeEnvFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestEnvFilesLookup(t *testing.T) {
+	dir := writeEnvFiles(t, map[string]string{
+		".env":                  "LAYER_A=env\nLAYER_B=env\nLAYER_C=env\nLAYER_D=env\nLAYER_HOST=localhost",
+		".env.local":            "LAYER_B=local",
+		".env.production":       "LAYER_C=production\nLAYER_URL=https://${LAYER_HOST}",
+		".env.production.local": "LAYER_D=production.local",
+		".env.test":             "LAYER_C=test",
+	})
+
+	tests := []struct {
+		name      string
+		opts      EnvFilesOptions
+		appEnv    string
+		want      map[string]string
+		wantFiles []string
+	}{
+		{
+			name: "production",
+			opts: EnvFilesOptions{Dir: dir, Environment: "production"},
+			want: map[string]string{
+				"LAYER_A":   "env",
+				"LAYER_B":   "local",
+				"LAYER_C":   "production",
+				"LAYER_D":   "production.local",
+				"LAYER_URL": "https://localhost",
+			},
+			wantFiles: []string{".env", ".env.local", ".env.production", ".env.production.local"},
+		},
+		{
+			name:      "test_skips_local",
+			opts:      EnvFilesOptions{Dir: dir, Environment: "test"},
+			want:      map[string]string{"LAYER_B": "env", "LAYER_C": "test"},
+			wantFiles: []string{".env", ".env.test"},
+		},
+		{
+			name:      "app_env",
+			opts:      EnvFilesOptions{Dir: dir},
+			appEnv:    "production",
+			want:      map[string]string{"LAYER_C": "production"},
+			wantFiles: []string{".env", ".env.local", ".env.production", ".env.production.local"},
+		},
+		{
+			name:      "no_environment",
+			opts:      EnvFilesOptions{Dir: dir},
+			want:      map[string]string{"LAYER_B": "local", "LAYER_C": "env"},
+			wantFiles: []string{".env", ".env.local"},
+		},
+		{
+			name:      "files",
+			opts:      EnvFilesOptions{Dir: dir, Files: []string{".env.test", ".env"}},
+			want:      map[string]string{"LAYER_C": "env"},
+			wantFiles: []string{".env.test", ".env"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tc.appEnv)
+
+			files, err := EnvFilesLookup(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for key, want := range tc.want {
+				if got, ok := files.Lookup(key); !ok || got != want {
+					t.Errorf("%s: expected %q, got %q (%v)", key, want, got, ok)
+				}
+			}
+
+			var gotFiles []string
+			for _, f := range files.Files() {
+				gotFiles = append(gotFiles, filepath.Base(f))
+			}
+			if !slices.Equal(gotFiles, tc.wantFiles) {
+				t.Errorf("expected files %v, got %v", tc.wantFiles, gotFiles)
+			}
+		})
+	}
+}
+
+func TestEnvFilesLookupSource(t *testing.T) {
+	dir := writeEnvFiles(t, map[string]string{
+		".env":         "SRC_A=1\nSRC_B=1",
+		".env.staging": "SRC_B=2",
+	})
+	t.Setenv("SRC_A", "os")
+
+	files, err := EnvFilesLookup(EnvFilesOptions{Dir: dir, Environment: "staging"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := files.Lookup("SRC_A"); v != "os" {
+		t.Errorf("expected OS value to win, got %q", v)
+	}
+	if src, _ := files.Source("SRC_A"); src != filepath.Join(dir, ".env") {
+		t.Errorf("SRC_A: unexpected source %q", src)
+	}
+	if src, _ := files.Source("SRC_B"); src != filepath.Join(dir, ".env.staging") {
+		t.Errorf("SRC_B: unexpected source %q", src)
+	}
+	if _, ok := files.Source("SRC_MISSING"); ok {
+		t.Error("expected no source for a missing key")
+	}
+}
+
+func TestEnvFilesLookupErrors(t *testing.T) {
+	dir := writeEnvFiles(t, map[string]string{
+		".env":            "A=1",
+		".env.production": "B='unterminated",
+	})
+
+	_, err := EnvFilesLookup(EnvFilesOptions{Dir: dir, Environment: "production"})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.File != filepath.Join(dir, ".env.production") {
+		t.Fatalf("expected a ParseError for .env.production, got %v", err)
+	}
+
+	_, err = EnvFilesLookup(EnvFilesOptions{Dir: dir, Files: []string{".env", ".env.missing"}})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+
+	_, err = EnvFilesLookup(EnvFilesOptions{
+		Dir:     dir,
+		Files:   []string{".env", ".env.missing"},
+		Options: []DotEnvOption{AllowMissingFile()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEnvFilesLookupFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"env/.env":            {Data: []byte("FSL_A=env\nFSL_B=env")},
+		"env/.env.production": {Data: []byte("FSL_B=production")},
+	}
+
+	files, err := EnvFilesLookup(EnvFilesOptions{FS: fsys, Dir: "env", Environment: "production"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := files.Lookup("FSL_A"); v != "env" {
+		t.Errorf("FSL_A: expected env, got %q", v)
+	}
+	if v, _ := files.Lookup("FSL_B"); v != "production" {
+		t.Errorf("FSL_B: expected production, got %q", v)
+	}
+	if src, _ := files.Source("FSL_B"); src != "env/.env.production" {
+		t.Errorf("FSL_B: unexpected source %q", src)
+	}
+	if got, want := files.Files(), []string{"env/.env", "env/.env.production"}; !slices.Equal(got, want) {
+		t.Errorf("expected files %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,175 @@
+package envconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// interpolateDotEnv replaces ${VAR}, $VAR and ${VAR:-default} references in the values
+// of entries, see DisableInterpolation for the rules.
+// Entries whose value can't be interpolated keep their raw value and are reported.
+func interpolateDotEnv(entries []dotenvEntry, o *dotenvOptions) ([]dotenvEntry, []*ParseError) {
+	in := &interpolator{
+		entries:   entries,
+		fileFirst: o.fileFirst,
+		lookupOS:  o.lookupOS(),
+		layers:    o.layers,
+		resolved:  make(map[string]string),
+	}
+
+	result := make([]dotenvEntry, len(entries))
+	var errs []*ParseError
+	for i, e := range entries {
+		value, err := in.resolve(i)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: e.line, Column: e.col, Err: err})
+			value = e.value
+		}
+		in.resolved[e.key] = value
+
+		e.value = value
+		result[i] = e
+	}
+	return result, errs
+}
+
+// interpolator resolves the entries of a file in order, so references only see earlier entries.
+type interpolator struct {
+	entries   []dotenvEntry
+	fileFirst bool
+	lookupOS  LookupEnv
+	layers    map[string]string // values of lower-precedence files, see EnvFilesLookup
+
+	resolved map[string]string // values of the entries before the current one, the latest per key
+}
+
+// resolve returns the interpolated value of the i-th entry.
+func (in *interpolator) resolve(i int) (string, error) {
+	e := in.entries[i]
+	if e.quote == '\'' || e.quote == '`' {
+		return e.value, nil
+	}
+	return in.expand(i, e.value, 0)
+}
+
+// expand interpolates s, a substring of the i-th entry's value starting at offset base.
+func (in *interpolator) expand(i int, s string, base int) (string, error) {
+	var b strings.Builder
+	for j := 0; j < len(s); j++ {
+		if s[j] != '$' || j+1 == len(s) || slices.Contains(in.entries[i].escapedDollars, base+j) {
+			b.WriteByte(s[j])
+			continue
+		}
+
+		if s[j+1] == '$' {
+			b.WriteByte('$')
+			j++
+			continue
+		}
+
+		if s[j+1] == '{' {
+			end := closingBrace(s, j+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated reference %q", s[j:])
+			}
+			value, err := in.expandBraced(i, s[j+2:end], base+j+2)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			j = end
+			continue
+		}
+
+		n := varNameLen(s[j+1:])
+		if n == 0 {
+			b.WriteByte(s[j])
+			continue
+		}
+		value, _ := in.lookup(s[j+1 : j+1+n])
+		b.WriteString(value)
+		j += n
+	}
+	return b.String(), nil
+}
+
+// expandBraced interpolates the content of ${...} starting at offset base of the i-th entry's value.
+func (in *interpolator) expandBraced(i int, ref string, base int) (string, error) {
+	n := varNameLen(ref)
+	name, op := ref[:n], ref[n:]
+	if name == "" {
+		return "", fmt.Errorf("invalid reference %q", "${"+ref+"}")
+	}
+
+	value, ok := in.lookup(name)
+	switch {
+	case op == "":
+		return value, nil
+	case strings.HasPrefix(op, ":-"):
+		if value != "" {
+			return value, nil
+		}
+		return in.expand(i, op[2:], base+n+2)
+	case strings.HasPrefix(op, "-"):
+		if ok {
+			return value, nil
+		}
+		return in.expand(i, op[1:], base+n+1)
+	}
+	return "", fmt.Errorf("invalid reference %q", "${"+ref+"}")
+}
+
+// lookup returns the value of key as referenced by an entry: from the OS environment,
+// the closest earlier entry of the file or a lower-precedence file.
+func (in *interpolator) lookup(key string) (string, bool) {
+	fromFiles := func() (string, bool) {
+		if value, ok := in.resolved[key]; ok {
+			return value, true
+		}
+		value, ok := in.layers[key]
+		return value, ok
+	}
+
+	if in.fileFirst {
+		if value, ok := fromFiles(); ok {
+			return value, true
+		}
+	}
+	if in.lookupOS != nil {
+		if value, ok := in.lookupOS(key); ok {
+			return value, true
+		}
+	}
+	return fromFiles()
+}
+
+// closingBrace returns the index of the brace closing a ${ reference whose content starts at start,
+// or -1 if there is none.
+func closingBrace(s string, start int) int {
+	depth := 1
+	for j := start; j < len(s); j++ {
+		switch s[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// varNameLen returns the length of the variable name [A-Za-z_][A-Za-z0-9_]* at the start of s.
+func varNameLen(s string) int {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9' {
+			continue
+		}
+		return i
+	}
+	return len(s)
+}
@@ -0,0 +1,109 @@
+package envconfig
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestDotEnvInterpolation(t *testing.T) {
+	t.Setenv("INTERP_OS_HOST", "os-host")
+	t.Setenv("INTERP_SHARED", "from-os")
+
+	tests := []struct {
+		name    string
+		content string
+		opts    []DotEnvOption
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:    "braced_and_plain",
+			content: "HOST=localhost\nPORT=8080\nBASE_URL=http://${HOST}:$PORT/api",
+			want:    map[string]string{"HOST": "localhost", "PORT": "8080", "BASE_URL": "http://localhost:8080/api"},
+		},
+		{
+			name:    "os_env",
+			content: `URL="https://${INTERP_OS_HOST}"`,
+			want:    map[string]string{"URL": "https://os-host"},
+		},
+		{
+			name:    "defaults",
+			content: "EMPTY=\nA=${INTERP_UNSET:-fallback}\nB=${EMPTY:-empty}\nC=${EMPTY-kept}\nD=${INTERP_UNSET-unset}\nE=${INTERP_UNSET:-${A}!}",
+			want:    map[string]string{"EMPTY": "", "A": "fallback", "B": "empty", "C": "", "D": "unset", "E": "fallback!"},
+		},
+		{
+			name:    "unset_is_empty",
+			content: "A=[${INTERP_UNSET}$INTERP_UNSET]",
+			want:    map[string]string{"A": "[]"},
+		},
+		{
+			name:    "literal_quotes_and_escapes",
+			content: "HOST=h\nA='${HOST}'\nB=`$HOST`\nC=\"\\${HOST} \\$HOST\"\nD=price: 5$ $1 $\nE=P@$$w0rd$$HOST",
+			want:    map[string]string{"HOST": "h", "A": "${HOST}", "B": "$HOST", "C": "${HOST} $HOST", "D": "price: 5$ $1 $", "E": "P@$w0rd$HOST"},
+		},
+		{
+			name:    "os_first",
+			content: "INTERP_SHARED=from-file\nA=${INTERP_SHARED}",
+			want:    map[string]string{"INTERP_SHARED": "from-file", "A": "from-os"},
+		},
+		{
+			name:    "file_first",
+			content: "INTERP_SHARED=from-file\nA=${INTERP_SHARED}",
+			opts:    []DotEnvOption{InterpolateFileFirst()},
+			want:    map[string]string{"INTERP_SHARED": "from-file", "A": "from-file"},
+		},
+		{
+			name:    "closest_earlier_definition",
+			content: "P=a\nP=${P}:b\nQ=$P",
+			want:    map[string]string{"P": "a:b", "Q": "a:b"},
+		},
+		{
+			name:    "later_entries_not_visible",
+			content: "URL=http://${HOST}\nHOST=example.com\nA=${B}\nB=x${A}",
+			want:    map[string]string{"URL": "http://", "HOST": "example.com", "A": "", "B": "x"},
+		},
+		{
+			name:    "self_reference",
+			content: "INTERP_OS_HOST=${INTERP_OS_HOST}:file\nLOCAL=a\nLOCAL=$LOCAL:b",
+			want:    map[string]string{"INTERP_OS_HOST": "os-host:file", "LOCAL": "a:b"},
+		},
+		{
+			name:    "unterminated_reference",
+			content: "A=${B\nC=1",
+			want:    map[string]string{"A": "${B", "C": "1"},
+			wantErr: `envconfig: line 1, column 1: unterminated reference "${B"`,
+		},
+		{
+			name:    "invalid_reference",
+			content: "A=${B:x}",
+			want:    map[string]string{"A": "${B:x}"},
+			wantErr: `envconfig: line 1, column 1: invalid reference "${B:x}"`,
+		},
+		{
+			name:    "disabled",
+			content: "HOST=h\nA=${HOST}",
+			opts:    []DotEnvOption{DisableInterpolation()},
+			want:    map[string]string{"HOST": "h", "A": "${HOST}"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDotEnv(strings.NewReader(tc.content), tc.opts...)
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.wantErr {
+				t.Fatalf("expected error %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package envconfig
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// LintKind classifies a Diagnostic of LintEnvFile.
+type LintKind string
+
+const (
+	LintDuplicateKey       LintKind = "duplicate-key"       // a key assigned more than once, the last one wins
+	LintInvalidKey         LintKind = "invalid-key"         // a key not matching [A-Za-z_][A-Za-z0-9_.-]*
+	LintUnterminatedQuote  LintKind = "unterminated-quote"  // a quoted value without a closing quote
+	LintUnquotedSpace      LintKind = "unquoted-space"      // an unquoted value containing whitespace
+	LintInconsistentExport LintKind = "inconsistent-export" // `export` used on some assignments but not others
+	LintTrailingWhitespace LintKind = "trailing-whitespace" // whitespace at the end of a line
+	LintSyntax             LintKind = "syntax"              // any other malformed line
+)
+
+// Diagnostic is an issue found by LintEnvFile at a 1-based line and byte column.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Kind    LintKind
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.File == "" {
+		return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Kind, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Column, d.Kind, d.Message)
+}
+
+// LintEnvFile checks the .env file at path with the parser used by EnvFileLookup and returns
+// its diagnostics ordered by position. The error is only set if the file can't be read.
+//
+// Usage in a pre-commit check:
+//
+//	diags, err := envconfig.LintEnvFile(".env.example")
+//	for _, d := range diags {
+//		fmt.Println(d) // .env.example:3:1: duplicate-key: "PORT" is already assigned on line 1
+//	}
+func LintEnvFile(path string) ([]Diagnostic, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: %w", err)
+	}
+	return lintDotEnv(string(data), path), nil
+}
+
+// LintDotEnv is like LintEnvFile, but reads the .env content from r.
+func LintDotEnv(r io.Reader) ([]Diagnostic, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("envconfig: reading .env: %w", err)
+	}
+	return lintDotEnv(string(data), ""), nil
+}
+
+func lintDotEnv(src, file string) []Diagnostic {
+	entries, parseErrs := parseDotEnv(src, newDotEnvOptions([]DotEnvOption{DisableInterpolation()}))
+
+	var diags []Diagnostic
+	add := func(line, col int, kind LintKind, format string, args ...any) {
+		diags = append(diags, Diagnostic{File: file, Line: line, Column: col, Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, err := range parseErrs {
+		kind := LintSyntax
+		switch {
+		case errors.Is(err, ErrInvalidKey):
+			kind = LintInvalidKey
+		case errors.Is(err, ErrUnterminatedQuote):
+			kind = LintUnterminatedQuote
+		}
+		add(err.Line, err.Column, kind, "%v", err.Err)
+	}
+
+	firstLine := make(map[string]int)
+	exports := 0
+	for _, e := range entries {
+		if line, ok := firstLine[e.key]; ok {
+			add(e.line, e.col, LintDuplicateKey, "%q is already assigned on line %d", e.key, line)
+		} else {
+			firstLine[e.key] = e.line
+		}
+
+		if e.quote == 0 && strings.ContainsAny(e.value, " \t") {
+			add(e.line, e.valueStart-e.start+e.col, LintUnquotedSpace, "value of %q contains whitespace, quote it", e.key)
+		}
+
+		if e.export {
+			exports++
+		}
+	}
+
+	// Flag the assignments deviating from the majority, or from the first assignment on a tie.
+	if exports > 0 && exports < len(entries) {
+		export := exports*2 > len(entries) || exports*2 == len(entries) && entries[0].export
+		for _, e := range entries {
+			if e.export == export {
+				continue
+			}
+			if export {
+				add(e.line, e.col, LintInconsistentExport, "%q is assigned without `export`, unlike the other keys", e.key)
+			} else {
+				add(e.line, e.col, LintInconsistentExport, "%q is assigned with `export`, unlike the other keys", e.key)
+			}
+		}
+	}
+
+	// Trailing whitespace inside multiline quoted values is part of the value.
+	inValue := make(map[int]bool)
+	for _, e := range entries {
+		for i := range strings.Count(src[e.start:e.valueEnd], "\n") {
+			inValue[e.line+i] = true
+		}
+	}
+	for i, line := range strings.Split(strings.TrimPrefix(src, "\uFEFF"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimRight(line, " \t")
+		if len(trimmed) < len(line) && !inValue[i+1] {
+			add(i+1, len(trimmed)+1, LintTrailingWhitespace, "trailing whitespace")
+		}
+	}
+
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+	return diags
+}
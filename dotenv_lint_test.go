@@ -0,0 +1,101 @@
+package envconfig
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLintDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "clean",
+			content: "# comment\nA=1\nB=\"two words\" # comment\nC='multi \nline'\n",
+		},
+		{
+			name:    "duplicate_key",
+			content: "A=1\nB=2\nA=3",
+			want:    []string{`3:1: duplicate-key: "A" is already assigned on line 1`},
+		},
+		{
+			name:    "invalid_key",
+			content: "1A=1\nA B=2",
+			want: []string{
+				`1:1: invalid-key: invalid key "1A"`,
+				`2:3: syntax: expected '=' after key "A"`,
+			},
+		},
+		{
+			name:    "unterminated_quote",
+			content: "A=1\nB=\"open\nC=3",
+			want:    []string{`2:3: unterminated-quote: unterminated quote "`},
+		},
+		{
+			name:    "unquoted_space",
+			content: "A=two words\nB = \"ok words\"",
+			want:    []string{`1:3: unquoted-space: value of "A" contains whitespace, quote it`},
+		},
+		{
+			name:    "inconsistent_export",
+			content: "export A=1\nexport B=2\nC=3",
+			want:    []string{`3:1: inconsistent-export: "C" is assigned without ` + "`export`" + `, unlike the other keys`},
+		},
+		{
+			name:    "inconsistent_export_minority",
+			content: "A=1\nB=2\n  export C=3",
+			want:    []string{`3:3: inconsistent-export: "C" is assigned with ` + "`export`" + `, unlike the other keys`},
+		},
+		{
+			name:    "trailing_whitespace",
+			content: "A=1 \r\n# comment\t\r\nB=\"x  \ny\"  \n",
+			want: []string{
+				"1:4: trailing-whitespace: trailing whitespace",
+				"2:10: trailing-whitespace: trailing whitespace",
+				"4:3: trailing-whitespace: trailing whitespace",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diags, err := LintDotEnv(strings.NewReader(tc.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, d := range diags {
+				got = append(got, d.String())
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("expected diagnostics:\n%s\ngot:\n%s", strings.Join(tc.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintEnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env.example")
+	if err := os.WriteFile(envFile, []byte("PORT=8080\nPORT=9090\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diags, err := LintEnvFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Diagnostic{File: envFile, Line: 2, Column: 1, Kind: LintDuplicateKey, Message: `"PORT" is already assigned on line 1`}
+	if len(diags) != 1 || diags[0] != want {
+		t.Fatalf("expected %v, got %v", want, diags)
+	}
+
+	if _, err := LintEnvFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -1,9 +1,14 @@
 package envconfig
 
 import (
+	"errors"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestEnvFileReader(t *testing.T) {
@@ -19,6 +24,7 @@ func TestEnvFileReader(t *testing.T) {
 		name        string
 		fileContent string
 		lookupKey   string
+		opts        []DotEnvOption
 		expected    string
 		exists      bool
 	}{
@@ -106,6 +112,142 @@ func TestEnvFileReader(t *testing.T) {
 			expected:    "value",
 			exists:      true,
 		},
+		{
+			name:        "multiline_double_quoted",
+			fileContent: "CERT=\"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\"\nNEXT=value",
+			lookupKey:   "CERT",
+			expected:    "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+			exists:      true,
+		},
+		{
+			name:        "after_multiline",
+			fileContent: "CERT=\"line1\nline2\" # comment\nNEXT=value",
+			lookupKey:   "NEXT",
+			expected:    "value",
+			exists:      true,
+		},
+		{
+			name:        "double_quoted_escapes",
+			fileContent: `ESCAPED="a\nb\tc \"quoted\" \\ \$HOME \x"`,
+			lookupKey:   "ESCAPED",
+			expected:    "a\nb\tc \"quoted\" \\ $HOME \\x",
+			exists:      true,
+		},
+		{
+			name:        "single_quoted_literal",
+			fileContent: `LITERAL='a\nb "c" \$HOME'`,
+			lookupKey:   "LITERAL",
+			expected:    `a\nb "c" \$HOME`,
+			exists:      true,
+		},
+		{
+			name:        "multiline_single_quoted",
+			fileContent: "MULTI='line1\nline2'",
+			lookupKey:   "MULTI",
+			expected:    "line1\nline2",
+			exists:      true,
+		},
+		{
+			name:        "backtick_quoted",
+			fileContent: "BACKTICK=`it's \"quoted\" \\n`",
+			lookupKey:   "BACKTICK",
+			expected:    `it's "quoted" \n`,
+			exists:      true,
+		},
+		{
+			name:        "crlf",
+			fileContent: "KEY1=value1\r\nMULTI=\"line1\r\nline2\"\r\nKEY2=value2\r\n",
+			lookupKey:   "MULTI",
+			expected:    "line1\nline2",
+			exists:      true,
+		},
+		{
+			name:        "crlf_unquoted",
+			fileContent: "KEY1=value1\r\nKEY2=value2\r\n",
+			lookupKey:   "KEY1",
+			expected:    "value1",
+			exists:      true,
+		},
+		{
+			name:        "bom",
+			fileContent: "\uFEFFFIRST=value",
+			lookupKey:   "FIRST",
+			expected:    "value",
+			exists:      true,
+		},
+		{
+			name:        "unterminated_quote_skipped",
+			fileContent: "BROKEN=\"no end\nVALID_LINE=value",
+			lookupKey:   "VALID_LINE",
+			expected:    "value",
+			exists:      true,
+		},
+		{
+			name:        "unterminated_quote",
+			fileContent: "BROKEN=\"no end\nVALID_LINE=value",
+			lookupKey:   "BROKEN",
+			expected:    "",
+			exists:      false,
+		},
+		{
+			name:        "empty_value",
+			fileContent: "EMPTY=\nEMPTY_QUOTED=\"\"",
+			lookupKey:   "EMPTY",
+			expected:    "",
+			exists:      true,
+		},
+		{
+			name:        "invalid_key",
+			fileContent: "1KEY=value\nVALID_LINE=value",
+			lookupKey:   "1KEY",
+			expected:    "",
+			exists:      false,
+		},
+		{
+			name:        "verbatim_by_default",
+			fileContent: "HOST=localhost\nBASE_URL=http://${HOST}",
+			lookupKey:   "BASE_URL",
+			expected:    "http://${HOST}",
+			exists:      true,
+		},
+		{
+			name:        "bcrypt_hash_verbatim",
+			fileContent: "HASH=$2a$10$abcDEF",
+			lookupKey:   "HASH",
+			expected:    "$2a$10$abcDEF",
+			exists:      true,
+		},
+		{
+			name:        "double_quoted_dollar_verbatim",
+			fileContent: `PASS="pa$word"`,
+			lookupKey:   "PASS",
+			expected:    "pa$word",
+			exists:      true,
+		},
+		{
+			name:        "interpolation",
+			fileContent: "HOST=localhost\nPORT=8080\nBASE_URL=http://${HOST}:${PORT}",
+			lookupKey:   "BASE_URL",
+			opts:        []DotEnvOption{Interpolate()},
+			expected:    "http://localhost:8080",
+			exists:      true,
+		},
+		{
+			name:        "uninterpolatable_kept_verbatim",
+			fileContent: "TPL=abc${def",
+			lookupKey:   "TPL",
+			opts:        []DotEnvOption{Interpolate()},
+			expected:    "abc${def",
+			exists:      true,
+		},
+		{
+			name:        "escaped_dollar",
+			fileContent: "PASS=P@$$w0rd",
+			lookupKey:   "PASS",
+			opts:        []DotEnvOption{Interpolate()},
+			expected:    "P@$w0rd",
+			exists:      true,
+		},
 		{
 			name:        "missing_key",
 			fileContent: "OTHER_KEY=value",
@@ -129,7 +271,7 @@ func TestEnvFileReader(t *testing.T) {
 				t.Fatalf("Failed to write test file: %v", err)
 			}
 
-			lookupFn := EnvFileLookup(envFile)
+			lookupFn := EnvFileLookup(envFile, tc.opts...)
 
 			value, exists := lookupFn(tc.lookupKey)
 			if exists != tc.exists {
@@ -156,3 +298,237 @@ func TestEnvFileReaderUnknownFile(t *testing.T) {
 		t.Fatalf("expected %q to have `exists` value, got: %q", env, v)
 	}
 }
+
+func TestParseDotEnvErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		opts    []DotEnvOption
+		want    map[string]string
+		wantErr []string
+		is      error
+	}{
+		{
+			name:    "valid",
+			content: "A=1\nexport B='2'\n",
+			want:    map[string]string{"A": "1", "B": "2"},
+		},
+		{
+			name:    "invalid_key",
+			content: "A=1\n  1KEY=value\nB=2",
+			want:    map[string]string{"A": "1", "B": "2"},
+			wantErr: []string{`envconfig: line 2, column 3: invalid key "1KEY"`},
+			is:      ErrInvalidKey,
+		},
+		{
+			name:    "missing_equals",
+			content: "A=1\nINVALID_LINE\n",
+			want:    map[string]string{"A": "1"},
+			wantErr: []string{`envconfig: line 2, column 13: expected '=' after key "INVALID_LINE"`},
+		},
+		{
+			name:    "missing_key",
+			content: "=value",
+			want:    map[string]string{},
+			wantErr: []string{`envconfig: line 1, column 1: missing key`},
+		},
+		{
+			name:    "unterminated_quote",
+			content: "A=1\nB=\"open\nC=3",
+			want:    map[string]string{"A": "1", "C": "3"},
+			wantErr: []string{`envconfig: line 2, column 3: unterminated quote "`},
+			is:      ErrUnterminatedQuote,
+		},
+		{
+			name:    "after_closing_quote",
+			content: "A='1' 2",
+			want:    map[string]string{},
+			wantErr: []string{`envconfig: line 1, column 7: unexpected '2' after closing quote`},
+		},
+		{
+			name:    "position_after_multiline",
+			content: "A=\"x\ny\"\n\n1B=2",
+			want:    map[string]string{"A": "x\ny"},
+			wantErr: []string{`envconfig: line 4, column 1: invalid key "1B"`},
+		},
+		{
+			name:    "line_too_long",
+			content: "A=1234567\nB=12\r\nC=\"1\n12345678\"",
+			opts:    []DotEnvOption{MaxLineSize(4)},
+			want:    map[string]string{"B": "12"},
+			wantErr: []string{
+				`envconfig: line 1, column 5: line too long: more than 4 bytes`,
+				`envconfig: line 4, column 5: line too long: more than 4 bytes`,
+			},
+			is: ErrLineTooLong,
+		},
+		{
+			name:    "line_size_unlimited",
+			content: "A=1234567",
+			opts:    []DotEnvOption{MaxLineSize(0)},
+			want:    map[string]string{"A": "1234567"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDotEnv(strings.NewReader(tc.content), tc.opts...)
+			if !maps.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != strings.Join(tc.wantErr, "\n") {
+				t.Fatalf("expected error %q, got %v", strings.Join(tc.wantErr, "\n"), err)
+			}
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected *ParseError, got %T", err)
+			}
+			if tc.is != nil && !errors.Is(err, tc.is) {
+				t.Errorf("expected error to match %v", tc.is)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envFile, []byte("LOAD_A=1\nLOAD_B=file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOAD_B", "os")
+
+	le, err := LoadEnvFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := le("LOAD_A"); !ok || v != "1" {
+		t.Errorf("LOAD_A: expected 1, got %q, %v", v, ok)
+	}
+	if v, ok := le("LOAD_B"); !ok || v != "os" {
+		t.Errorf("LOAD_B: expected OS value to win, got %q, %v", v, ok)
+	}
+
+	t.Run("parse_error", func(t *testing.T) {
+		badFile := filepath.Join(dir, "bad.env")
+		if err := os.WriteFile(badFile, []byte("A=1\nB='2\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := LoadEnvFile(badFile)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *ParseError, got %v", err)
+		}
+		if perr.File != badFile || perr.Line != 2 || perr.Column != 3 {
+			t.Errorf("unexpected position %s:%d:%d", perr.File, perr.Line, perr.Column)
+		}
+		if want := "envconfig: " + badFile + ":2:3: unterminated quote '"; err.Error() != want {
+			t.Errorf("expected %q, got %q", want, err.Error())
+		}
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := LoadEnvFile(filepath.Join(dir, "missing.env"))
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected fs.ErrNotExist, got %v", err)
+		}
+
+		le, err := LoadEnvFile(filepath.Join(dir, "missing.env"), AllowMissingFile())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v, ok := le("LOAD_B"); !ok || v != "os" {
+			t.Errorf("expected OS fallback, got %q, %v", v, ok)
+		}
+	})
+}
+
+func TestDotEnvPrecedence(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	content := "PREC_SHARED=file\nPREC_FILE=file\nPREC_REF=${PREC_SHARED}\nPREC_OS_REF=[${PREC_OS_ONLY}]"
+	if err := os.WriteFile(envFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PREC_SHARED", "os")
+	t.Setenv("PREC_OS_ONLY", "os")
+
+	tests := []struct {
+		name    string
+		opts    []DotEnvOption
+		want    map[string]string
+		missing []string
+	}{
+		{
+			name: "os_wins",
+			want: map[string]string{"PREC_SHARED": "os", "PREC_FILE": "file", "PREC_OS_ONLY": "os", "PREC_REF": "os", "PREC_OS_REF": "[os]"},
+		},
+		{
+			name: "file_overrides_os",
+			opts: []DotEnvOption{FileOverridesOS()},
+			want: map[string]string{"PREC_SHARED": "file", "PREC_FILE": "file", "PREC_OS_ONLY": "os", "PREC_REF": "file", "PREC_OS_REF": "[os]"},
+		},
+		{
+			name:    "file_only",
+			opts:    []DotEnvOption{FileOnly()},
+			want:    map[string]string{"PREC_SHARED": "file", "PREC_FILE": "file", "PREC_REF": "file", "PREC_OS_REF": "[]"},
+			missing: []string{"PREC_OS_ONLY"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			loaded, err := LoadEnvFile(envFile, tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lookups := map[string]LookupEnv{
+				"EnvFileLookup": EnvFileLookup(envFile, append([]DotEnvOption{Interpolate()}, tc.opts...)...),
+				"LoadEnvFile":   loaded,
+			}
+
+			for name, lookup := range lookups {
+				for key, want := range tc.want {
+					if got, ok := lookup(key); !ok || got != want {
+						t.Errorf("%s: %s: expected %q, got %q (%v)", name, key, want, got, ok)
+					}
+				}
+				for _, key := range tc.missing {
+					if got, ok := lookup(key); ok {
+						t.Errorf("%s: %s: expected missing key, got %q", name, key, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEnvFSLookup(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/defaults.env": {Data: []byte("FS_PORT=8080\nFS_URL=http://localhost:${FS_PORT}")},
+	}
+	t.Setenv("FS_OS", "os")
+
+	le := EnvFSLookup(fsys, "config/defaults.env", Interpolate())
+	if v, ok := le("FS_URL"); !ok || v != "http://localhost:8080" {
+		t.Errorf("expected FS_URL from the file system, got %q, %v", v, ok)
+	}
+	if v, _ := EnvFSLookup(fsys, "config/defaults.env")("FS_URL"); v != "http://localhost:${FS_PORT}" {
+		t.Errorf("expected FS_URL verbatim without Interpolate, got %q", v)
+	}
+	if v, ok := le("FS_OS"); !ok || v != "os" {
+		t.Errorf("expected OS fallback, got %q, %v", v, ok)
+	}
+
+	missing := EnvFSLookup(fsys, "missing.env")
+	if v, ok := missing("FS_OS"); !ok || v != "os" {
+		t.Errorf("expected OS fallback for a missing file, got %q, %v", v, ok)
+	}
+}
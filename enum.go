@@ -0,0 +1,53 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Enum can be implemented by types accepting only a fixed set of values, e.g.:
+//
+//	type Mode string
+//
+//	func (Mode) Values() []string { return []string{"fast", "safe", "paranoid"} }
+//
+// Values not listed are rejected with an error listing the allowed ones.
+// The `envEnum` tag on a field takes precedence over Values.
+type Enum interface {
+	Values() []string
+}
+
+var enumType = reflect.TypeFor[Enum]()
+
+// enumValues returns the allowed values for t, from the `envEnum` tag or the Enum interface.
+// Pointers, slices, arrays and maps are not enums themselves, their elements are checked instead.
+func (s *fieldSpec) enumValues(t reflect.Type) []string {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return nil
+	}
+
+	if s.enum != nil {
+		return s.enum
+	}
+	if reflect.PointerTo(t).Implements(enumType) {
+		return reflect.New(t).Interface().(Enum).Values()
+	}
+	return nil
+}
+
+// checkEnum validates value against the allowed values of t and returns its canonical spelling.
+func (s *fieldSpec) checkEnum(t reflect.Type, value string) (string, error) {
+	values := s.enumValues(t)
+	if values == nil {
+		return value, nil
+	}
+
+	for _, v := range values {
+		if v == value || (s.enumFold && strings.EqualFold(v, value)) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%q: invalid value %q, allowed values: %s", s.key, value, strings.Join(values, ", "))
+}
@@ -0,0 +1,114 @@
+package envconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+type mode string
+
+func (mode) Values() []string {
+	return []string{"fast", "safe", "paranoid"}
+}
+
+// level implements encoding.TextUnmarshaler, the enum check must run before it.
+type level string
+
+func (l *level) UnmarshalText(text []byte) error {
+	*l = level("level:" + string(text))
+	return nil
+}
+
+func TestReadEnum(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "MODE":
+			return "safe", true
+		case "MODE_UPPER":
+			return "PARANOID", true
+		case "FORMAT":
+			return "json", true
+		case "MODES":
+			return "fast,Safe", true
+		case "LEVEL":
+			return "Debug", true
+		case "BY_SERVICE":
+			return "api=fast,db=safe", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Mode      mode            `env:"MODE"`
+		ModeFold  *mode           `env:"MODE_UPPER" envEnumFold:"true"`
+		Format    string          `env:"FORMAT" envEnum:"text, json"`
+		Default   string          `env:"MISSING" envEnum:"text,json" envDefault:"text"`
+		Modes     []mode          `env:"MODES" envEnumFold:"true"`
+		Level     level           `env:"LEVEL" envEnum:"debug,info" envEnumFold:"true"`
+		ByService map[string]mode `env:"BY_SERVICE"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Mode != "safe" {
+		t.Errorf("Mode: unexpected %q", cfg.Mode)
+	}
+	if cfg.ModeFold == nil || *cfg.ModeFold != "paranoid" {
+		t.Errorf("ModeFold: expected canonical spelling, got %v", cfg.ModeFold)
+	}
+	if cfg.Format != "json" || cfg.Default != "text" {
+		t.Errorf("Format: unexpected %q, %q", cfg.Format, cfg.Default)
+	}
+	if len(cfg.Modes) != 2 || cfg.Modes[1] != "safe" {
+		t.Errorf("Modes: unexpected %q", cfg.Modes)
+	}
+	if cfg.Level != "level:debug" {
+		t.Errorf("Level: unexpected %q", cfg.Level)
+	}
+	if cfg.ByService["db"] != "safe" {
+		t.Errorf("ByService: unexpected %v", cfg.ByService)
+	}
+}
+
+func TestReadEnumErrors(t *testing.T) {
+	le := func(key string) (string, bool) {
+		return "Fast", true
+	}
+
+	t.Run("interface", func(t *testing.T) {
+		var cfg struct {
+			Mode mode `env:"MODE"`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "Mode" failed to populate: "MODE": invalid value "Fast", allowed values: fast, safe, paranoid`)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		var cfg struct {
+			Format []string `env:"FORMAT" envEnum:"text,json"`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "Format" failed to populate: "FORMAT": invalid value "Fast", allowed values: text, json`)
+	})
+
+	t.Run("unmarshaler", func(t *testing.T) {
+		var cfg struct {
+			Level level `env:"LEVEL" envEnum:"debug,info"`
+		}
+		err := envconfig.Read(&cfg, le)
+		if err == nil || !strings.Contains(err.Error(), `allowed values: debug, info`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("empty_tag", func(t *testing.T) {
+		var cfg struct {
+			Format string `env:"FORMAT" envEnum:""`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "Format": tag "envEnum" can't be empty`)
+	})
+}
@@ -1,10 +1,16 @@
 package envconfig
 
 import (
+	"context"
 	"encoding"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io/fs"
+	"math"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -13,6 +19,10 @@ import (
 
 type LookupEnv = func(string) (string, bool)
 
+// LookupEnvContext is a lookup that can fail, e.g. when backed by a remote secret manager.
+// A non-nil error aborts ReadContext; ok == false means the key is not set.
+type LookupEnvContext = func(ctx context.Context, key string) (value string, ok bool, err error)
+
 // Read populates holder (a pointer to struct) using the provided lookup function to resolve values.
 //
 // Usage:
@@ -31,6 +41,7 @@ type LookupEnv = func(string) (string, bool)
 //
 //	By default Read uses os.LookupEnv. You may pass a custom lookup function,
 //	e.g., envconfig.Read(&cfg, myLookup) where myLookup has signature func(string) (string, bool).
+//	See ReadWith for further options.
 //
 // Tags (per field):
 //   - `env:"NAME"`        : the environment variable name for this field.
@@ -46,6 +57,32 @@ type LookupEnv = func(string) (string, bool)
 //     anonymous ones). Applies a prefix to all descendant
 //     leaf env names. Prefixes are joined with "_".
 //     Example: `envPrefix:"DB"` -> DB_HOST, DB_PORT.
+//   - `envLayout:"LAYOUT"`: layout for time.Time fields. Either a time.Parse layout
+//     (e.g. "2006-01-02"), a name of a time package layout constant
+//     (e.g. "RFC1123", "DateOnly"), or "unix" / "unixms" for epoch timestamps.
+//     Defaults to RFC 3339.
+//   - `envTimezone:"TZ"`  : location (as accepted by time.LoadLocation) used for
+//     time.Time values without zone information. Defaults to UTC.
+//   - `envSchemes:"a,b"`  : allowed schemes for url.URL fields (case-insensitive).
+//   - `envRequirePort:"true"`: url.URL fields must have a port, string fields must
+//     be a host:port pair.
+//   - `envUnit:"bytes"`   : integer fields accept human-friendly sizes like "512",
+//     "10MB" (powers of 1000) or "10MiB" (powers of 1024).
+//   - `envBase:"N"`       : base for integer fields (2 to 36). `envBase:"0"` detects the
+//     base from the prefix ("0x", "0o", "0b", "0") and accepts "_" separators.
+//   - `envBool:"lenient"` : bool fields also accept yes/no, y/n, on/off, enable(d)/disable(d),
+//     case-insensitively. `envBool:"strict"` opts out when WithLenientBool is used.
+//   - `envTrim:"true"`, `envCase:"lower|upper"`, `envExpandHome:"true"`: transform the
+//     value (and each slice, array and map element) before parsing, in this order.
+//     `envExpandHome` replaces a leading "~" with the user's home directory.
+//   - `envEnum:"a,b,c"`   : allowed values (for slices, arrays and maps: of each element value).
+//     Types implementing Enum are checked against their Values without the tag.
+//   - `envEnumFold:"true"`: match enum values case-insensitively, storing the canonical spelling.
+//   - `envFormat:"json"`  : decode the value with encoding/json into any field type
+//     (structs, slices, maps, ...). Unknown object fields are errors.
+//   - `envResolve:"true"` : resolve "scheme:ref" references (file://, base64:, env://, and
+//     schemes added with RegisterResolver or WithResolver). `envResolve:"false"` opts out
+//     when WithReferenceResolution is used.
 //
 // Embedded and named struct fields:
 //   - Embedded (anonymous) and named struct fields are treated "flat" by default
@@ -60,12 +97,17 @@ type LookupEnv = func(string) (string, bool)
 //
 // Supported field types:
 //   - primitives: string, bool, all int/uint sizes, float32/64
-//   - time.Duration (parsed via time.ParseDuration)
+//   - time.Duration (parsed via time.ParseDuration, additionally accepting "d" and "w" units)
+//   - time.Time (parsed using `envLayout` and `envTimezone`)
+//   - *time.Location (parsed via time.LoadLocation)
+//   - os.FileMode / fs.FileMode (octal with a leading "0" or "0o", e.g. "0640"; otherwise as uint32)
+//   - url.URL, net.IP, net.IPNet (CIDR), netip.Addr, netip.AddrPort, netip.Prefix, mail.Address
 //   - arrays, slices: comma-separated values (e.g. "a,b,c")
 //   - maps: comma-separated k=v pairs (e.g. "k1=v1,k2=v2"); split on first "="
 //   - pointers to any supported type (allocated only when a value is set;
 //     left nil otherwise - including pointer-to-struct fields where no descendant env var is found)
 //   - any type implementing (in the priority) json.Unmarshaler > encoding.BinaryUnmarshaler > encoding.TextUnmarshaler
+//   - any type with a parser registered via RegisterParser or WithParser (takes precedence over all of the above)
 //
 // Precedence per leaf field:
 //  1. If lookupEnv returns (value, ok==true), that value is used as-is
@@ -76,7 +118,7 @@ type LookupEnv = func(string) (string, bool)
 //
 // Errors when:
 //   - `env` tag is empty
-//   - Struct with `env` tag but no unmarshal interface
+//   - Struct with `env` tag but no unmarshal interface (and no `envFormat`)
 //   - Exported values without `env` tag
 //   - EnvCollector with value (non-pointer) receiver
 //   - A required field is missing and no default is provided
@@ -84,16 +126,43 @@ type LookupEnv = func(string) (string, bool)
 //   - Struct fields specify both `env` and `envPrefix`
 //   - `envPrefix` is empty when present
 //   - Parsing/conversion failures (returned errors includes the env key)
+//   - A lookup set with WithLookupContext fails, or the context passed to ReadContext is done
 //   - Unsupported leaf types (that do not implement a supported unmarshal interface)
 func Read[T any](holder *T, lookupEnv ...LookupEnv) error {
+	var opts []Option
+	if len(lookupEnv) >= 1 {
+		opts = append(opts, WithLookup(lookupEnv[0]))
+	}
+	return ReadWith(holder, opts...)
+}
+
+// ReadWith populates holder (a pointer to struct) like Read, configured with opts.
+//
+// Usage:
+//
+//	err := envconfig.ReadWith(&cfg,
+//	  envconfig.WithLookup(envconfig.EnvFileLookup(".env")),
+//	  envconfig.WithIntBaseDetection(),
+//	)
+func ReadWith[T any](holder *T, opts ...Option) error {
+	return ReadContext(context.Background(), holder, opts...)
+}
+
+// ReadContext is like ReadWith, passing ctx to a lookup set with WithLookupContext.
+// Reading stops with the context error once ctx is done.
+// Lookup errors are returned wrapped together with the key.
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	err := envconfig.ReadContext(ctx, &cfg, envconfig.WithLookupContext(secrets.Lookup))
+func ReadContext[T any](ctx context.Context, holder *T, opts ...Option) error {
 	if holder == nil {
 		return fmt.Errorf("envconfig: nil holder")
 	}
 
-	lookupEnvFunc := os.LookupEnv
-	if len(lookupEnv) >= 1 {
-		lookupEnvFunc = lookupEnv[0]
-	}
+	o := newOptions(ctx, opts)
 
 	tp := reflect.TypeFor[*T]()
 	if tp.Kind() != reflect.Pointer {
@@ -105,7 +174,15 @@ func Read[T any](holder *T, lookupEnv ...LookupEnv) error {
 		return fmt.Errorf("envconfig.Read only accepts a struct, got %q", tp.Kind().String())
 	}
 
-	_, err := read(lookupEnvFunc, "", holder)
+	if o.batch != nil {
+		lookup, err := o.batchLookup(tp)
+		if err != nil {
+			return err
+		}
+		o.lookup = lookup
+	}
+
+	_, err := read(o, "", "", holder)
 	return err
 }
 
@@ -126,11 +203,20 @@ type EnvGetter interface {
 }
 
 type getter struct {
-	lookup LookupEnv
+	opts *options
+	path string // Go field path of the EnvCollector
+	err  error  // first error of Lookup, which can't return it
 }
 
 func (g *getter) Lookup(key string) (string, bool) {
-	return g.lookup(key)
+	val, ok, err := g.opts.lookupKey(key)
+	if err != nil {
+		if g.err == nil {
+			g.err = err
+		}
+		return "", false
+	}
+	return val, ok
 }
 
 func (g *getter) ReadValue(key string, target any) error {
@@ -139,12 +225,34 @@ func (g *getter) ReadValue(key string, target any) error {
 		return fmt.Errorf("%q not a pointer", v.Type())
 	}
 
-	val, ok := g.lookup(key)
+	val, ok, err := g.opts.lookupKey(key)
+	if err != nil {
+		return err
+	}
 	if !ok {
 		return nil
 	}
 
-	return setValue(v, val)
+	spec, err := newFieldSpec(g.opts, key, "")
+	if err != nil {
+		return err
+	}
+
+	info := FieldInfo{Key: key, Path: g.path, Type: v.Type().Elem()}
+
+	val, err = g.opts.decodeHook(info, val)
+	if err != nil {
+		return err
+	}
+
+	val, err = g.opts.resolveRef(spec, val)
+	if err != nil {
+		return err
+	}
+	if err := setValue(v, val, spec); err != nil {
+		return err
+	}
+	return g.opts.postDecodeHook(info, v.Elem())
 }
 
 func (g *getter) ReadIntoStruct(prefix string, target any) error {
@@ -155,7 +263,7 @@ func (g *getter) ReadIntoStruct(prefix string, target any) error {
 	if tp.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("envconfig: Read target must be a pointer to struct, got pointer to %q", tp.Elem().Kind())
 	}
-	_, err := read(g.lookup, prefix+"_", target)
+	_, err := read(g.opts, prefix+"_", g.path, target)
 	return err
 }
 
@@ -170,7 +278,7 @@ type EnvCollector interface {
 	CollectEnv(env EnvGetter) error
 }
 
-func read(le func(string) (string, bool), prefix string, holder any) (bool, error) {
+func read(o *options, prefix, path string, holder any) (bool, error) {
 	holderPtr := reflect.ValueOf(holder)
 	holderValue := holderPtr.Elem()
 	fields := reflect.VisibleFields(holderValue.Type())
@@ -178,15 +286,18 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 	populated := false
 
 	for _, field := range fields {
-		if field.PkgPath != "" {
+		if field.PkgPath != "" || promoted(holderValue.Type(), field) {
 			continue
 		}
 
 		fieldVal := holderValue.FieldByName(field.Name)
 		ft := field.Type
 
+		// A parser registered for a pointer type parses the value into the field itself.
+		_, pointerParser := findParser(o.parsers, ft)
+
 		allocated := false
-		if ft.Kind() == reflect.Pointer {
+		if ft.Kind() == reflect.Pointer && ft != locationPtrType && !pointerParser {
 			if fieldVal.IsNil() {
 				fieldVal.Set(reflect.New(ft.Elem()))
 			}
@@ -202,8 +313,13 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 			}
 
 			if collector, ok := fieldVal.Addr().Interface().(EnvCollector); ok {
-				get := &getter{lookup: le}
-				if err := collector.CollectEnv(get); err != nil {
+				get := &getter{opts: o, path: joinPath(path, field.Name)}
+				err := collector.CollectEnv(get)
+				// A lookup error takes precedence, err may just be the reaction to the key looking unset.
+				if get.err != nil {
+					return false, fmt.Errorf("envconfig: %q CollectEnv failed: %w", field.Name, get.err)
+				}
+				if err != nil {
 					return false, fmt.Errorf("envconfig: %q CollectEnv failed: %w", field.Name, err)
 				}
 				populated = true
@@ -233,7 +349,7 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 
 		if ft.Kind() == reflect.Struct {
 			if !hasEnv && !hasPrefix {
-				childPopulated, err := read(le, prefix, fieldVal.Addr().Interface())
+				childPopulated, err := read(o, prefix, joinPath(path, field.Name), fieldVal.Addr().Interface())
 				if err != nil {
 					return false, err
 				}
@@ -248,7 +364,7 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 				if prefix != "" {
 					realPref = prefix + realPref
 				}
-				childPopulated, err := read(le, realPref, fieldVal.Addr().Interface())
+				childPopulated, err := read(o, realPref, joinPath(path, field.Name), fieldVal.Addr().Interface())
 				if err != nil {
 					return false, err
 				}
@@ -266,7 +382,10 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 			return false, fmt.Errorf("envconfig: field %q does not have \"env\" tag", field.Name)
 		}
 
-		envVal, ok := le(prefix + env)
+		envVal, ok, err := o.lookupKey(prefix + env)
+		if err != nil {
+			return false, err
+		}
 		if !ok {
 			defaultVal, hasDefault := field.Tag.Lookup("envDefault")
 			if !hasDefault && field.Tag.Get("envRequired") == "true" {
@@ -284,49 +403,279 @@ func read(le func(string) (string, bool), prefix string, holder any) (bool, erro
 
 		populated = true
 
-		if fieldVal.CanAddr() {
-			var fn func(val []byte) error
-			if u, ok := fieldVal.Addr().Interface().(encoding.TextUnmarshaler); ok {
-				fn = u.UnmarshalText
-			}
-			if u, ok := fieldVal.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
-				fn = u.UnmarshalBinary
-			}
-			if u, ok := fieldVal.Addr().Interface().(json.Unmarshaler); ok {
-				fn = u.UnmarshalJSON
-			}
+		spec, err := newFieldSpec(o, prefix+env, field.Tag)
+		if err != nil {
+			return false, fmt.Errorf("envconfig: field %q: %w", field.Name, err)
+		}
 
-			if fn != nil {
-				if err := fn([]byte(envVal)); err != nil {
-					return false, fmt.Errorf("envconfig: error decoding %q field: %w", field.Name, err)
-				}
-				continue
-			}
-			if fieldVal.Kind() == reflect.Struct {
-				return false, fmt.Errorf("envconfig: field %q is a struct with \"env\" tag but does not implement encoding.TextUnmarshaler / encoding.BinaryUnmarshaler / json.Unmarshaler", field.Name)
-			}
+		info := FieldInfo{
+			Key:  prefix + env,
+			Path: joinPath(path, field.Name),
+			Type: field.Type,
+			Tag:  field.Tag,
+		}
+
+		envVal, err = o.decodeHook(info, envVal)
+		if err != nil {
+			return false, err
+		}
+
+		envVal, err = o.resolveRef(spec, envVal)
+		if err != nil {
+			return false, err
+		}
+
+		envVal, err = spec.transform(envVal)
+		if err != nil {
+			return false, fmt.Errorf("envconfig: field %q: %w", field.Name, err)
+		}
+
+		if err := decodeField(field, fieldVal, envVal, spec); err != nil {
+			return false, err
 		}
 
-		if err := setValue(fieldVal, envVal); err != nil {
-			return false, fmt.Errorf("envconfig: field %q failed to populate: %w", field.Name, err)
+		if err := o.postDecodeHook(info, holderValue.FieldByName(field.Name)); err != nil {
+			return false, err
 		}
 	}
 
 	return populated, nil
 }
 
+// decodeField decodes envVal into a leaf field using its unmarshal interface or setValue.
+func decodeField(field reflect.StructField, fieldVal reflect.Value, envVal string, spec *fieldSpec) error {
+	if spec.format == formatJSON {
+		if err := decodeJSON(fieldVal, envVal); err != nil {
+			return fmt.Errorf("envconfig: field %q failed to decode JSON from %q: %w", field.Name, spec.key, err)
+		}
+		return nil
+	}
+
+	if fieldVal.CanAddr() && !spec.hasParser(fieldVal.Type()) {
+		var fn func(val []byte) error
+		if u, ok := fieldVal.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			fn = u.UnmarshalText
+		}
+		if u, ok := fieldVal.Addr().Interface().(encoding.BinaryUnmarshaler); ok {
+			fn = u.UnmarshalBinary
+		}
+		if u, ok := fieldVal.Addr().Interface().(json.Unmarshaler); ok {
+			fn = u.UnmarshalJSON
+		}
+
+		if fn != nil {
+			envVal, err := spec.checkEnum(fieldVal.Type(), envVal)
+			if err != nil {
+				return fmt.Errorf("envconfig: field %q failed to populate: %w", field.Name, err)
+			}
+			if err := fn([]byte(envVal)); err != nil {
+				return fmt.Errorf("envconfig: error decoding %q field: %w", field.Name, err)
+			}
+			return nil
+		}
+		if fieldVal.Kind() == reflect.Struct {
+			return fmt.Errorf("envconfig: field %q is a struct with \"env\" tag but does not implement encoding.TextUnmarshaler / encoding.BinaryUnmarshaler / json.Unmarshaler", field.Name)
+		}
+	}
+
+	if err := setValue(fieldVal, envVal, spec); err != nil {
+		return fmt.Errorf("envconfig: field %q failed to populate: %w", field.Name, err)
+	}
+	return nil
+}
+
+// promoted reports whether field of t is promoted from an exported embedded struct,
+// whose fields are read with the struct itself.
+// Fields of unexported embedded structs are only reachable through promotion.
+func promoted(t reflect.Type, field reflect.StructField) bool {
+	return len(field.Index) > 1 && t.Field(field.Index[0]).IsExported()
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
 var (
 	durationType     = reflect.TypeFor[time.Duration]()
 	byteSliceType    = reflect.TypeFor[[]byte]()
+	timeType         = reflect.TypeFor[time.Time]()
+	locationPtrType  = reflect.TypeFor[*time.Location]()
+	urlType          = reflect.TypeFor[url.URL]()
+	ipType           = reflect.TypeFor[net.IP]()
+	ipNetType        = reflect.TypeFor[net.IPNet]()
+	addrType         = reflect.TypeFor[netip.Addr]()
+	addrPortType     = reflect.TypeFor[netip.AddrPort]()
+	prefixType       = reflect.TypeFor[netip.Prefix]()
+	mailAddressType  = reflect.TypeFor[mail.Address]()
+	fileModeType     = reflect.TypeFor[fs.FileMode]()
 	envCollectorType = reflect.TypeFor[EnvCollector]()
 )
 
-func setValue(inp reflect.Value, value string) error {
+// builtinTypes are parsed by setValue directly, even if they implement one of the unmarshal interfaces.
+var builtinTypes = map[reflect.Type]func(inp reflect.Value, value string, spec *fieldSpec) error{
+	timeType:        setTime,
+	locationPtrType: setLocation,
+	urlType:         setURL,
+	ipType:          setIP,
+	ipNetType:       setIPNet,
+	addrType:        setAddr,
+	addrPortType:    setAddrPort,
+	prefixType:      setPrefix,
+	mailAddressType: setMailAddress,
+	fileModeType:    setFileMode,
+}
+
+// fieldSpec holds per-field parsing settings taken from struct tags.
+type fieldSpec struct {
+	key string
+
+	layout   string
+	location *time.Location
+
+	schemes     []string
+	requirePort bool
+
+	unit        string
+	base        int
+	lenientBool bool
+
+	trim       bool
+	caseMode   string
+	expandHome bool
+
+	enum     []string
+	enumFold bool
+
+	format  string
+	resolve bool
+
+	parsers map[reflect.Type]parserFunc
+}
+
+func newFieldSpec(o *options, key string, tag reflect.StructTag) (*fieldSpec, error) {
+	spec := &fieldSpec{key: key, base: 10, lenientBool: o.lenientBool, parsers: o.parsers, resolve: o.resolveRefs}
+	if o.intBaseDetection {
+		spec.base = 0
+	}
+
+	if layout, ok := tag.Lookup("envLayout"); ok {
+		if layout == "" {
+			return nil, fmt.Errorf("tag \"envLayout\" can't be empty")
+		}
+		spec.layout = layout
+	}
+
+	if tz, ok := tag.Lookup("envTimezone"); ok {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid \"envTimezone\" tag: %w", err)
+		}
+		spec.location = loc
+	}
+
+	if schemes, ok := tag.Lookup("envSchemes"); ok {
+		spec.schemes = split(schemes)
+		if len(spec.schemes) == 0 {
+			return nil, fmt.Errorf("tag \"envSchemes\" can't be empty")
+		}
+	}
+
+	spec.requirePort = tag.Get("envRequirePort") == "true"
+
+	if unit, ok := tag.Lookup("envUnit"); ok {
+		if unit != unitBytes {
+			return nil, fmt.Errorf("unsupported \"envUnit\" tag %q", unit)
+		}
+		spec.unit = unit
+	}
+
+	if base, ok := tag.Lookup("envBase"); ok {
+		b, err := strconv.Atoi(base)
+		if err != nil || (b != 0 && (b < 2 || b > 36)) {
+			return nil, fmt.Errorf("invalid \"envBase\" tag %q", base)
+		}
+		spec.base = b
+	}
+
+	spec.trim = tag.Get("envTrim") == "true"
+	spec.expandHome = tag.Get("envExpandHome") == "true"
+
+	if mode, ok := tag.Lookup("envCase"); ok {
+		if mode != "lower" && mode != "upper" {
+			return nil, fmt.Errorf("invalid \"envCase\" tag %q, expected \"lower\" or \"upper\"", mode)
+		}
+		spec.caseMode = mode
+	}
+
+	if enum, ok := tag.Lookup("envEnum"); ok {
+		spec.enum = split(enum)
+		if len(spec.enum) == 0 {
+			return nil, fmt.Errorf("tag \"envEnum\" can't be empty")
+		}
+	}
+	spec.enumFold = tag.Get("envEnumFold") == "true"
+
+	if resolve, ok := tag.Lookup("envResolve"); ok {
+		spec.resolve = resolve == "true"
+	}
+
+	if format, ok := tag.Lookup("envFormat"); ok {
+		if format != formatJSON {
+			return nil, fmt.Errorf("unsupported \"envFormat\" tag %q", format)
+		}
+		spec.format = format
+	}
+
+	if mode, ok := tag.Lookup("envBool"); ok {
+		switch mode {
+		case "lenient":
+			spec.lenientBool = true
+		case "strict":
+			spec.lenientBool = false
+		default:
+			return nil, fmt.Errorf("invalid \"envBool\" tag %q, expected \"lenient\" or \"strict\"", mode)
+		}
+	}
+
+	return spec, nil
+}
+
+// hasParser reports whether t is parsed by a registered or built-in parser rather than an unmarshal interface.
+func (s *fieldSpec) hasParser(t reflect.Type) bool {
+	if _, ok := findParser(s.parsers, t); ok {
+		return true
+	}
+	_, ok := builtinTypes[t]
+	return ok
+}
+
+func setValue(inp reflect.Value, value string, spec *fieldSpec) error {
+	value, err := spec.checkEnum(inp.Type(), value)
+	if err != nil {
+		return err
+	}
+
+	if parse, ok := findParser(spec.parsers, inp.Type()); ok {
+		v, err := parse(value)
+		if err != nil {
+			return err
+		}
+		inp.Set(v)
+		return nil
+	}
+
+	if parse, ok := builtinTypes[inp.Type()]; ok {
+		return parse(inp, value, spec)
+	}
+
 	if inp.Kind() == reflect.Pointer {
 		if inp.IsNil() {
 			inp.Set(reflect.New(inp.Type().Elem()))
 		}
-		return setValue(inp.Elem(), value)
+		return setValue(inp.Elem(), value, spec)
 	}
 
 	if inp.CanAddr() {
@@ -352,7 +701,7 @@ func setValue(inp reflect.Value, value string) error {
 
 	switch inp.Type() {
 	case durationType:
-		d, err := time.ParseDuration(value)
+		d, err := parseDuration(value)
 		if err != nil {
 			return err
 		}
@@ -366,8 +715,19 @@ func setValue(inp reflect.Value, value string) error {
 
 	switch inp.Kind() {
 	case reflect.String:
+		if err := checkHostPort(value, spec); err != nil {
+			return err
+		}
 		inp.SetString(value)
 	case reflect.Bool:
+		if spec.lenientBool {
+			b, err := parseLenientBool(value)
+			if err != nil {
+				return err
+			}
+			inp.SetBool(b)
+			return nil
+		}
 		b, err := strconv.ParseBool(value)
 		if err != nil {
 			return err
@@ -375,14 +735,30 @@ func setValue(inp reflect.Value, value string) error {
 		inp.SetBool(b)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		bits := inp.Type().Bits()
-		i, err := strconv.ParseInt(value, 10, bits)
+		if spec.unit == unitBytes {
+			n, err := parseBytes(value, math.MaxUint64>>(65-bits))
+			if err != nil {
+				return err
+			}
+			inp.SetInt(int64(n))
+			return nil
+		}
+		i, err := strconv.ParseInt(value, spec.base, bits)
 		if err != nil {
 			return err
 		}
 		inp.SetInt(i)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		bits := inp.Type().Bits()
-		u, err := strconv.ParseUint(value, 10, bits)
+		if spec.unit == unitBytes {
+			n, err := parseBytes(value, math.MaxUint64>>(64-bits))
+			if err != nil {
+				return err
+			}
+			inp.SetUint(n)
+			return nil
+		}
+		u, err := strconv.ParseUint(value, spec.base, bits)
 		if err != nil {
 			return err
 		}
@@ -400,19 +776,25 @@ func setValue(inp reflect.Value, value string) error {
 			return fmt.Errorf("array needs %d elements, got %d", inp.Len(), len(arr))
 		}
 		for i := 0; i < inp.Len(); i++ {
-			err := setValue(inp.Index(i), arr[i])
+			item, err := spec.transform(arr[i])
 			if err != nil {
 				return err
 			}
+			if err := setValue(inp.Index(i), item, spec); err != nil {
+				return err
+			}
 		}
 	case reflect.Slice:
 		arr := split(value)
 		for i := range arr {
-			elem := reflect.New(inp.Type().Elem()).Elem()
-			err := setValue(elem, arr[i])
+			item, err := spec.transform(arr[i])
 			if err != nil {
 				return err
 			}
+			elem := reflect.New(inp.Type().Elem()).Elem()
+			if err := setValue(elem, item, spec); err != nil {
+				return err
+			}
 			inp.Set(reflect.Append(inp, elem))
 		}
 	case reflect.Map:
@@ -420,20 +802,30 @@ func setValue(inp reflect.Value, value string) error {
 		if len(arr) == 0 {
 			return nil
 		}
+		// `envEnum` constrains map values, keys are only checked if their type implements Enum.
+		keySpec := *spec
+		keySpec.enum = nil
+
 		mp := reflect.MakeMap(inp.Type())
 		for i := range arr {
 			kv := strings.SplitN(arr[i], "=", 2)
 			if len(kv) != 2 {
 				return fmt.Errorf("invalid map value %s", value)
 			}
+			keyItem, err := spec.transform(strings.TrimSpace(kv[0]))
+			if err != nil {
+				return err
+			}
 			key := reflect.New(inp.Type().Key()).Elem()
-			err := setValue(key, strings.TrimSpace(kv[0]))
+			if err := setValue(key, keyItem, &keySpec); err != nil {
+				return err
+			}
+			item, err := spec.transform(kv[1])
 			if err != nil {
 				return err
 			}
 			val := reflect.New(inp.Type().Elem()).Elem()
-			err = setValue(val, kv[1])
-			if err != nil {
+			if err := setValue(val, item, spec); err != nil {
 				return err
 			}
 			mp.SetMapIndex(key, val)
@@ -0,0 +1,47 @@
+package envconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// setFileMode parses octal permissions with a leading "0" or "0o", like "0640" or "0o640".
+// The setuid (04000), setgid (02000) and sticky (01000) bits map to their fs.FileMode counterparts.
+// Other values are parsed as an integer in the field's base, as for uint32, so "420" stays 0o644.
+func setFileMode(inp reflect.Value, value string, spec *fieldSpec) error {
+	digits, octal := strings.CutPrefix(strings.ToLower(value), "0o")
+	if !octal && len(value) > 1 && value[0] == '0' && strings.Trim(value, "01234567") == "" {
+		digits, octal = value[1:], true
+	}
+
+	if !octal {
+		n, err := strconv.ParseUint(value, spec.base, 32)
+		if err != nil {
+			return fmt.Errorf("%q: invalid file mode %q", spec.key, value)
+		}
+		inp.Set(reflect.ValueOf(fs.FileMode(n)))
+		return nil
+	}
+
+	n, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || n > 0o7777 {
+		return fmt.Errorf("%q: invalid file mode %q", spec.key, value)
+	}
+
+	mode := fs.FileMode(n) & fs.ModePerm
+	if n&0o4000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if n&0o2000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if n&0o1000 != 0 {
+		mode |= fs.ModeSticky
+	}
+
+	inp.Set(reflect.ValueOf(mode))
+	return nil
+}
@@ -0,0 +1,27 @@
+package envconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// formatJSON is the `envFormat` value decoding a field from JSON.
+const formatJSON = "json"
+
+// decodeJSON strictly decodes value into target: unknown object fields and trailing data are errors.
+func decodeJSON(target reflect.Value, value string) error {
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(target.Addr().Interface()); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("unexpected data after JSON value")
+	}
+	return nil
+}
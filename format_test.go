@@ -0,0 +1,131 @@
+package envconfig_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadJSONFormat(t *testing.T) {
+	type RateLimit struct {
+		Default int `json:"default"`
+		Burst   int `json:"burst"`
+	}
+	type Route struct {
+		Path   string `json:"path"`
+		Target string `json:"target"`
+	}
+
+	le := func(key string) (string, bool) {
+		switch key {
+		case "RATE_LIMITS":
+			return `{"default":10,"burst":20}`, true
+		case "ROUTES":
+			return `[{"path":"/api","target":"http://api"},{"path":"/","target":"http://web"}]`, true
+		case "WEIGHTS":
+			return `{"a":1.5,"b":2}`, true
+		case "PORTS":
+			return `[80, 443]`, true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		RateLimits RateLimit          `env:"RATE_LIMITS" envFormat:"json"`
+		RatePtr    *RateLimit         `env:"RATE_LIMITS" envFormat:"json"`
+		Routes     []Route            `env:"ROUTES" envFormat:"json"`
+		Weights    map[string]float64 `env:"WEIGHTS" envFormat:"json"`
+		Ports      []int              `env:"PORTS" envFormat:"json"`
+		Default    RateLimit          `env:"MISSING" envFormat:"json" envDefault:"{\"default\":1}"`
+		Missing    *RateLimit         `env:"MISSING" envFormat:"json"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	want := RateLimit{Default: 10, Burst: 20}
+	if cfg.RateLimits != want {
+		t.Errorf("RateLimits: unexpected %+v", cfg.RateLimits)
+	}
+	if cfg.RatePtr == nil || *cfg.RatePtr != want {
+		t.Errorf("RatePtr: unexpected %+v", cfg.RatePtr)
+	}
+	wantRoutes := []Route{{Path: "/api", Target: "http://api"}, {Path: "/", Target: "http://web"}}
+	if !reflect.DeepEqual(cfg.Routes, wantRoutes) {
+		t.Errorf("Routes: unexpected %+v", cfg.Routes)
+	}
+	if !reflect.DeepEqual(cfg.Weights, map[string]float64{"a": 1.5, "b": 2}) {
+		t.Errorf("Weights: unexpected %v", cfg.Weights)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []int{80, 443}) {
+		t.Errorf("Ports: unexpected %v", cfg.Ports)
+	}
+	if cfg.Default != (RateLimit{Default: 1}) {
+		t.Errorf("Default: unexpected %+v", cfg.Default)
+	}
+	if cfg.Missing != nil {
+		t.Errorf("Missing: expected nil, got %+v", cfg.Missing)
+	}
+}
+
+func TestReadJSONFormatErrors(t *testing.T) {
+	type RateLimit struct {
+		Default int `json:"default"`
+	}
+
+	tests := []struct {
+		name    string
+		envVal  string
+		wantErr string
+	}{
+		{
+			name:    "unknown_field",
+			envVal:  `{"default":10,"brust":20}`,
+			wantErr: `envconfig: field "V" failed to decode JSON from "V": json: unknown field "brust"`,
+		},
+		{
+			name:    "invalid_json",
+			envVal:  `{"default":`,
+			wantErr: `envconfig: field "V" failed to decode JSON from "V": unexpected EOF`,
+		},
+		{
+			name:    "trailing_data",
+			envVal:  `{"default":10} {}`,
+			wantErr: `envconfig: field "V" failed to decode JSON from "V": unexpected data after JSON value`,
+		},
+		{
+			name:    "wrong_type",
+			envVal:  `{"default":"ten"}`,
+			wantErr: `cannot unmarshal string`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			le := func(key string) (string, bool) {
+				return tt.envVal, true
+			}
+
+			var cfg struct {
+				V RateLimit `env:"V" envFormat:"json"`
+			}
+			err := envconfig.Read(&cfg, le)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("unsupported_format", func(t *testing.T) {
+		le := func(key string) (string, bool) {
+			return "a: b", true
+		}
+		var cfg struct {
+			V RateLimit `env:"V" envFormat:"yaml"`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "V": unsupported "envFormat" tag "yaml"`)
+	})
+}
@@ -0,0 +1,79 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldInfo describes a field read from the environment, see Keys and DecodeHook.
+type FieldInfo struct {
+	// Key is the full env key, including all prefixes, e.g. "DB_TLS_CERT".
+	Key string
+	// Path is the Go field path from the holder, e.g. "DB.TLS.Cert".
+	// For values read by an EnvCollector it is the path of the collector field.
+	Path string
+	// Type is the declared type of the field.
+	Type reflect.Type
+	// Tag is the struct tag of the field, empty for values read by an EnvCollector.
+	Tag reflect.StructTag
+}
+
+// Default returns the value of the `envDefault` tag and whether it is present.
+func (f FieldInfo) Default() (string, bool) {
+	return f.Tag.Lookup("envDefault")
+}
+
+// Required reports whether the field is tagged with `envRequired:"true"`.
+func (f FieldInfo) Required() bool {
+	return f.Tag.Get("envRequired") == "true"
+}
+
+// DecodeHook is called with the raw value (from the lookup or envDefault) before it is converted,
+// and before value references (see WithReferenceResolution) are resolved.
+// The returned value is converted instead, an error aborts reading.
+type DecodeHook func(field FieldInfo, value string) (string, error)
+
+// PostDecodeHook is called with the field value after a successful conversion.
+// An error aborts reading.
+type PostDecodeHook func(field FieldInfo, value reflect.Value) error
+
+// WithDecodeHook adds a hook called before each value is converted.
+// Hooks run in the order they were added, each receiving the value returned by the previous one.
+//
+// Usage:
+//
+//	envconfig.WithDecodeHook(func(f envconfig.FieldInfo, v string) (string, error) {
+//	  return strings.TrimSpace(v), nil
+//	})
+func WithDecodeHook(hook DecodeHook) Option {
+	return func(o *options) {
+		o.decodeHooks = append(o.decodeHooks, hook)
+	}
+}
+
+// WithPostDecodeHook adds a hook called after each value is converted.
+// Hooks run in the order they were added.
+func WithPostDecodeHook(hook PostDecodeHook) Option {
+	return func(o *options) {
+		o.postDecodeHooks = append(o.postDecodeHooks, hook)
+	}
+}
+
+func (o *options) decodeHook(field FieldInfo, value string) (string, error) {
+	for _, hook := range o.decodeHooks {
+		var err error
+		if value, err = hook(field, value); err != nil {
+			return "", fmt.Errorf("envconfig: decode hook failed for %q: %w", field.Key, err)
+		}
+	}
+	return value, nil
+}
+
+func (o *options) postDecodeHook(field FieldInfo, value reflect.Value) error {
+	for _, hook := range o.postDecodeHooks {
+		if err := hook(field, value); err != nil {
+			return fmt.Errorf("envconfig: post decode hook failed for %q: %w", field.Key, err)
+		}
+	}
+	return nil
+}
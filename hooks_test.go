@@ -0,0 +1,171 @@
+package envconfig_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestDecodeHooks(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "NAME":
+			return "  service  ", true
+		case "DB_TLS_CERT":
+			return "secret://cert", true
+		case "CREDS":
+			return "0", true
+		case "CREDS_0_USER":
+			return "user0", true
+		}
+		return "", false
+	}
+
+	type TLS struct {
+		Cert string `env:"CERT"`
+	}
+	var cfg struct {
+		Name string `env:"NAME"`
+		Port int    `env:"PORT" envDefault:"8080"`
+		DB   struct {
+			TLS *TLS `envPrefix:"TLS"`
+		} `envPrefix:"DB"`
+		Credentials Credentials
+	}
+
+	var pre, post []string
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithDecodeHook(func(f envconfig.FieldInfo, v string) (string, error) {
+			pre = append(pre, f.Key+"|"+f.Path+"|"+f.Type.String())
+			return strings.TrimSpace(v), nil
+		}),
+		envconfig.WithDecodeHook(func(f envconfig.FieldInfo, v string) (string, error) {
+			if ref, ok := strings.CutPrefix(v, "secret://"); ok {
+				return "resolved-" + ref, nil
+			}
+			return v, nil
+		}),
+		envconfig.WithPostDecodeHook(func(f envconfig.FieldInfo, v reflect.Value) error {
+			post = append(post, f.Path+"="+reflect.Indirect(v).String())
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "service" {
+		t.Errorf("Name: expected trimmed value, got %q", cfg.Name)
+	}
+	if cfg.DB.TLS == nil || cfg.DB.TLS.Cert != "resolved-cert" {
+		t.Errorf("Cert: expected resolved value, got %+v", cfg.DB.TLS)
+	}
+
+	wantPre := []string{
+		"NAME|Name|string",
+		"PORT|Port|int",
+		"DB_TLS_CERT|DB.TLS.Cert|string",
+		"CREDS|Credentials|[]string",
+		"CREDS_0_USER|Credentials.User|string",
+	}
+	if !reflect.DeepEqual(pre, wantPre) {
+		t.Errorf("decode hook calls:\n got %q\nwant %q", pre, wantPre)
+	}
+
+	wantPost := []string{
+		"Name=service",
+		"Port=<int Value>",
+		"DB.TLS.Cert=resolved-cert",
+		"Credentials=<[]string Value>",
+		"Credentials.User=user0",
+	}
+	if !reflect.DeepEqual(post, wantPost) {
+		t.Errorf("post decode hook calls:\n got %q\nwant %q", post, wantPost)
+	}
+}
+
+func TestDecodeHooksEmbedded(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "X", "Y", "AUDIT_X":
+			return "v-" + key, true
+		}
+		return "", false
+	}
+
+	type Base struct {
+		X string `env:"X"`
+	}
+	type Audit struct {
+		Base
+	}
+	type extra struct {
+		Y string `env:"Y"`
+	}
+	var cfg struct {
+		Base
+		Audit `envPrefix:"AUDIT"`
+		extra
+	}
+
+	var pre, post []string
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithDecodeHook(func(f envconfig.FieldInfo, v string) (string, error) {
+			pre = append(pre, f.Key+"|"+f.Path)
+			return v, nil
+		}),
+		envconfig.WithPostDecodeHook(func(f envconfig.FieldInfo, v reflect.Value) error {
+			post = append(post, f.Path)
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.X != "v-X" || cfg.Audit.X != "v-AUDIT_X" || cfg.Y != "v-Y" {
+		t.Errorf("unexpected values: %+v", cfg)
+	}
+
+	wantPre := []string{"X|Base.X", "AUDIT_X|Audit.Base.X", "Y|Y"}
+	if !reflect.DeepEqual(pre, wantPre) {
+		t.Errorf("decode hook calls:\n got %q\nwant %q", pre, wantPre)
+	}
+	wantPost := []string{"Base.X", "Audit.Base.X", "Y"}
+	if !reflect.DeepEqual(post, wantPost) {
+		t.Errorf("post decode hook calls:\n got %q\nwant %q", post, wantPost)
+	}
+}
+
+func TestDecodeHookErrors(t *testing.T) {
+	le := func(key string) (string, bool) {
+		return "value", true
+	}
+
+	var cfg struct {
+		Sub struct {
+			V string `env:"V"`
+		} `envPrefix:"SUB"`
+	}
+
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithDecodeHook(func(envconfig.FieldInfo, string) (string, error) {
+			return "", errors.New("boom")
+		}),
+	)
+	assertErr(t, err, `envconfig: decode hook failed for "SUB_V": boom`)
+
+	err = envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithPostDecodeHook(func(envconfig.FieldInfo, reflect.Value) error {
+			return errors.New("boom")
+		}),
+	)
+	assertErr(t, err, `envconfig: post decode hook failed for "SUB_V": boom`)
+}
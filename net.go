@@ -0,0 +1,94 @@
+package envconfig
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+func setURL(inp reflect.Value, value string, spec *fieldSpec) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%q: invalid URL: %w", spec.key, err)
+	}
+
+	if len(spec.schemes) > 0 && !slices.ContainsFunc(spec.schemes, func(s string) bool { return strings.EqualFold(s, u.Scheme) }) {
+		return fmt.Errorf("%q: URL scheme %q is not allowed, expected one of: %s", spec.key, u.Scheme, strings.Join(spec.schemes, ", "))
+	}
+	if spec.requirePort && u.Port() == "" {
+		return fmt.Errorf("%q: URL %q has no port", spec.key, value)
+	}
+
+	inp.Set(reflect.ValueOf(*u))
+	return nil
+}
+
+// checkHostPort validates value as host:port when the field requires a port.
+func checkHostPort(value string, spec *fieldSpec) error {
+	if !spec.requirePort {
+		return nil
+	}
+	if _, port, err := net.SplitHostPort(value); err != nil || port == "" {
+		return fmt.Errorf("%q: %q is not a host:port pair", spec.key, value)
+	}
+	return nil
+}
+
+func setIP(inp reflect.Value, value string, spec *fieldSpec) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("%q: invalid IP address %q", spec.key, value)
+	}
+	inp.Set(reflect.ValueOf(ip))
+	return nil
+}
+
+func setIPNet(inp reflect.Value, value string, spec *fieldSpec) error {
+	_, ipNet, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("%q: %w", spec.key, err)
+	}
+	inp.Set(reflect.ValueOf(*ipNet))
+	return nil
+}
+
+func setAddr(inp reflect.Value, value string, spec *fieldSpec) error {
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return fmt.Errorf("%q: %w", spec.key, err)
+	}
+	inp.Set(reflect.ValueOf(addr))
+	return nil
+}
+
+func setAddrPort(inp reflect.Value, value string, spec *fieldSpec) error {
+	addrPort, err := netip.ParseAddrPort(value)
+	if err != nil {
+		return fmt.Errorf("%q: %w", spec.key, err)
+	}
+	inp.Set(reflect.ValueOf(addrPort))
+	return nil
+}
+
+func setPrefix(inp reflect.Value, value string, spec *fieldSpec) error {
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return fmt.Errorf("%q: %w", spec.key, err)
+	}
+	inp.Set(reflect.ValueOf(prefix))
+	return nil
+}
+
+func setMailAddress(inp reflect.Value, value string, spec *fieldSpec) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("%q: invalid mail address %q: %w", spec.key, value, err)
+	}
+	inp.Set(reflect.ValueOf(*addr))
+	return nil
+}
@@ -0,0 +1,198 @@
+package envconfig_test
+
+import (
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadNetworkTypes(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "URL":
+			return "https://api.example.com:8443/v1", true
+		case "IP":
+			return "10.0.0.1", true
+		case "CIDR":
+			return "10.0.0.5/8", true
+		case "ADDR":
+			return "::1", true
+		case "ADDR_PORT":
+			return "127.0.0.1:5432", true
+		case "PREFIX":
+			return "192.168.0.0/16", true
+		case "MAIL":
+			return "Ops Team <ops@example.com>", true
+		case "HOST_PORT":
+			return "db.internal:5432", true
+		case "ALLOWED":
+			return "10.0.0.0/8, 172.16.0.0/12", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		URL      url.URL        `env:"URL" envSchemes:"http,https" envRequirePort:"true"`
+		URLPtr   *url.URL       `env:"URL"`
+		NoURL    *url.URL       `env:"MISSING"`
+		IP       net.IP         `env:"IP"`
+		CIDR     net.IPNet      `env:"CIDR"`
+		Addr     netip.Addr     `env:"ADDR"`
+		AddrPort netip.AddrPort `env:"ADDR_PORT"`
+		Prefix   netip.Prefix   `env:"PREFIX"`
+		Mail     *mail.Address  `env:"MAIL"`
+		HostPort string         `env:"HOST_PORT" envRequirePort:"true"`
+		Allowed  []net.IPNet    `env:"ALLOWED"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.URL.Host != "api.example.com:8443" || cfg.URL.Path != "/v1" {
+		t.Errorf("URL: unexpected %v", cfg.URL.String())
+	}
+	if cfg.URLPtr == nil || cfg.URLPtr.String() != "https://api.example.com:8443/v1" {
+		t.Errorf("URLPtr: unexpected %v", cfg.URLPtr)
+	}
+	if cfg.NoURL != nil {
+		t.Errorf("NoURL: expected nil, got %v", cfg.NoURL)
+	}
+	if !cfg.IP.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("IP: unexpected %v", cfg.IP)
+	}
+	if cfg.CIDR.String() != "10.0.0.0/8" {
+		t.Errorf("CIDR: unexpected %v", cfg.CIDR.String())
+	}
+	if cfg.Addr != netip.IPv6Loopback() {
+		t.Errorf("Addr: unexpected %v", cfg.Addr)
+	}
+	if cfg.AddrPort.Port() != 5432 {
+		t.Errorf("AddrPort: unexpected %v", cfg.AddrPort)
+	}
+	if cfg.Prefix.String() != "192.168.0.0/16" {
+		t.Errorf("Prefix: unexpected %v", cfg.Prefix)
+	}
+	if cfg.Mail == nil || cfg.Mail.Name != "Ops Team" || cfg.Mail.Address != "ops@example.com" {
+		t.Errorf("Mail: unexpected %v", cfg.Mail)
+	}
+	if cfg.HostPort != "db.internal:5432" {
+		t.Errorf("HostPort: unexpected %v", cfg.HostPort)
+	}
+	if len(cfg.Allowed) != 2 || cfg.Allowed[1].String() != "172.16.0.0/12" {
+		t.Errorf("Allowed: unexpected %v", cfg.Allowed)
+	}
+}
+
+func TestReadNetworkTypesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sut     func(le envconfig.LookupEnv) error
+		envVal  string
+		wantErr string
+	}{
+		{
+			name: "url_scheme_not_allowed",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V url.URL `env:"V" envSchemes:"http,https"`
+				}{}, le)
+			},
+			envVal:  "ftp://example.com",
+			wantErr: `"V": URL scheme "ftp" is not allowed, expected one of: http, https`,
+		},
+		{
+			name: "url_missing_port",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V *url.URL `env:"V" envRequirePort:"true"`
+				}{}, le)
+			},
+			envVal:  "http://example.com",
+			wantErr: `"V": URL "http://example.com" has no port`,
+		},
+		{
+			name: "invalid_url",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V url.URL `env:"V"`
+				}{}, le)
+			},
+			envVal:  "http://[::1",
+			wantErr: `"V": invalid URL`,
+		},
+		{
+			name: "host_port_missing_port",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V string `env:"V" envRequirePort:"true"`
+				}{}, le)
+			},
+			envVal:  "db.internal",
+			wantErr: `"V": "db.internal" is not a host:port pair`,
+		},
+		{
+			name: "invalid_ip",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V net.IP `env:"V"`
+				}{}, le)
+			},
+			envVal:  "300.0.0.1",
+			wantErr: `"V": invalid IP address "300.0.0.1"`,
+		},
+		{
+			name: "invalid_cidr",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V net.IPNet `env:"V"`
+				}{}, le)
+			},
+			envVal:  "10.0.0.1",
+			wantErr: `"V": invalid CIDR address`,
+		},
+		{
+			name: "invalid_addr_port",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V netip.AddrPort `env:"V"`
+				}{}, le)
+			},
+			envVal:  "127.0.0.1",
+			wantErr: `"V": `,
+		},
+		{
+			name: "invalid_mail",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V mail.Address `env:"V"`
+				}{}, le)
+			},
+			envVal:  "not an address",
+			wantErr: `"V": invalid mail address "not an address"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			le := func(key string) (string, bool) {
+				if key == "V" {
+					return tt.envVal, true
+				}
+				return "", false
+			}
+
+			err := tt.sut(le)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package envconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// Option configures ReadWith and ReadContext.
+type Option func(*options)
+
+type options struct {
+	ctx              context.Context
+	lookup           LookupEnvContext
+	intBaseDetection bool
+	lenientBool      bool
+	parsers          map[reflect.Type]parserFunc
+	decodeHooks      []DecodeHook
+	postDecodeHooks  []PostDecodeHook
+	batch            BatchLookup
+	resolvers        map[string]Resolver
+	resolveRefs      bool
+}
+
+func newOptions(ctx context.Context, opts []Option) *options {
+	o := &options{ctx: ctx, lookup: adaptLookup(os.LookupEnv)}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// lookupKey resolves key, failing once the context is done.
+func (o *options) lookupKey(key string) (string, bool, error) {
+	if err := o.ctx.Err(); err != nil {
+		return "", false, fmt.Errorf("envconfig: lookup %q: %w", key, err)
+	}
+
+	val, ok, err := o.lookup(o.ctx, key)
+	if err != nil {
+		return "", false, fmt.Errorf("envconfig: lookup %q: %w", key, err)
+	}
+	return val, ok, nil
+}
+
+func adaptLookup(lookupEnv LookupEnv) LookupEnvContext {
+	return func(_ context.Context, key string) (string, bool, error) {
+		val, ok := lookupEnv(key)
+		return val, ok, nil
+	}
+}
+
+// WithLookup sets the function used to resolve env values. Defaults to os.LookupEnv.
+func WithLookup(lookupEnv LookupEnv) Option {
+	return func(o *options) {
+		o.lookup = adaptLookup(lookupEnv)
+	}
+}
+
+// WithLookupContext sets a context-aware function used to resolve env values,
+// replacing WithLookup. Its errors abort reading.
+func WithLookupContext(lookup LookupEnvContext) Option {
+	return func(o *options) {
+		o.lookup = lookup
+	}
+}
+
+// WithIntBaseDetection makes all integer fields detect the base from the value prefix
+// ("0x", "0o", "0b", or "0" for octal) and accept "_" digit separators, e.g. "0x1F", "0o755", "1_000_000".
+// It is equivalent to tagging every integer field with `envBase:"0"`.
+func WithIntBaseDetection() Option {
+	return func(o *options) {
+		o.intBaseDetection = true
+	}
+}
+
+// WithLenientBool makes all bool fields accept, case-insensitively, yes/no, y/n, on/off,
+// enable(d)/disable(d) in addition to the strconv.ParseBool spellings.
+// It is equivalent to tagging every bool field with `envBool:"lenient"`.
+func WithLenientBool() Option {
+	return func(o *options) {
+		o.lenientBool = true
+	}
+}
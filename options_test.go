@@ -0,0 +1,223 @@
+package envconfig_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadWithLookup(t *testing.T) {
+	le := func(key string) (string, bool) {
+		if key == "PORT" {
+			return "8080", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Port int `env:"PORT"`
+	}
+	if err := envconfig.ReadWith(&cfg, envconfig.WithLookup(le)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("expected 8080, got %d", cfg.Port)
+	}
+}
+
+func TestReadIntBase(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "HEX":
+			return "0x1F", true
+		case "OCT":
+			return "0o755", true
+		case "UNDERSCORE":
+			return "1_000_000", true
+		case "PLAIN_HEX":
+			return "ff", true
+		case "MODE":
+			return "0640", true
+		case "MODE_PREFIXED":
+			return "0o2775", true
+		case "MODE_DECIMAL":
+			return "420", true
+		}
+		return "", false
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		var cfg struct {
+			Hex        int         `env:"HEX" envBase:"0"`
+			Oct        uint32      `env:"OCT" envBase:"0"`
+			Underscore int64       `env:"UNDERSCORE" envBase:"0"`
+			PlainHex   uint8       `env:"PLAIN_HEX" envBase:"16"`
+			Mode       os.FileMode `env:"MODE"`
+			Prefixed   fs.FileMode `env:"MODE_PREFIXED"`
+			Decimal    fs.FileMode `env:"MODE_DECIMAL"`
+		}
+		if err := envconfig.Read(&cfg, le); err != nil {
+			t.Fatal(err)
+		}
+
+		if cfg.Hex != 31 || cfg.Oct != 0o755 || cfg.Underscore != 1_000_000 || cfg.PlainHex != 255 {
+			t.Errorf("unexpected values: %+v", cfg)
+		}
+		if cfg.Mode != 0o640 {
+			t.Errorf("Mode: expected %v, got %v", fs.FileMode(0o640), cfg.Mode)
+		}
+		if cfg.Prefixed != fs.ModeSetgid|0o775 {
+			t.Errorf("Prefixed: expected %v, got %v", fs.ModeSetgid|0o775, cfg.Prefixed)
+		}
+		if cfg.Decimal != 0o644 {
+			t.Errorf("Decimal: expected %v, got %v", fs.FileMode(0o644), cfg.Decimal)
+		}
+	})
+
+	t.Run("option", func(t *testing.T) {
+		var cfg struct {
+			Hex int  `env:"HEX"`
+			Oct uint `env:"OCT"`
+		}
+		if err := envconfig.ReadWith(&cfg, envconfig.WithLookup(le), envconfig.WithIntBaseDetection()); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Hex != 31 || cfg.Oct != 0o755 {
+			t.Errorf("unexpected values: %+v", cfg)
+		}
+
+		var decimal struct {
+			Decimal int `env:"UNDERSCORE" envBase:"10"`
+		}
+		err := envconfig.ReadWith(&decimal, envconfig.WithLookup(le), envconfig.WithIntBaseDetection())
+		if err == nil || !strings.Contains(err.Error(), `field "Decimal" failed to populate`) {
+			t.Fatalf("expected envBase tag to override the option, got %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var cfg struct {
+			Hex int `env:"HEX" envBase:"1"`
+		}
+		err := envconfig.Read(&cfg, le)
+		assertErr(t, err, `envconfig: field "Hex": invalid "envBase" tag "1"`)
+
+		var mode struct {
+			Mode fs.FileMode `env:"HEX"`
+		}
+		err = envconfig.Read(&mode, le)
+		assertErr(t, err, `envconfig: field "Mode" failed to populate: "HEX": invalid file mode "0x1F"`)
+	})
+}
+
+func TestReadContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("lookup_receives_context", func(t *testing.T) {
+		lookup := func(ctx context.Context, key string) (string, bool, error) {
+			if key == "TENANT" {
+				return ctx.Value(ctxKey{}).(string), true, nil
+			}
+			return "", false, nil
+		}
+
+		var cfg struct {
+			Tenant string `env:"TENANT"`
+			Port   int    `env:"PORT" envDefault:"8080"`
+		}
+		ctx := context.WithValue(context.Background(), ctxKey{}, "acme")
+		if err := envconfig.ReadContext(ctx, &cfg, envconfig.WithLookupContext(lookup)); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Tenant != "acme" || cfg.Port != 8080 {
+			t.Errorf("unexpected values: %+v", cfg)
+		}
+	})
+
+	t.Run("lookup_error", func(t *testing.T) {
+		errUnavailable := errors.New("secret manager unavailable")
+		lookup := func(ctx context.Context, key string) (string, bool, error) {
+			return "", false, errUnavailable
+		}
+
+		var cfg struct {
+			DB struct {
+				Password string `env:"PASSWORD" envRequired:"true"`
+			} `envPrefix:"DB"`
+		}
+		err := envconfig.ReadContext(context.Background(), &cfg, envconfig.WithLookupContext(lookup))
+		assertErr(t, err, `envconfig: lookup "DB_PASSWORD": secret manager unavailable`)
+		if !errors.Is(err, errUnavailable) {
+			t.Errorf("expected error to wrap the lookup error")
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		lookup := func(ctx context.Context, key string) (string, bool, error) {
+			calls++
+			cancel()
+			return "value", true, nil
+		}
+
+		var cfg struct {
+			A string `env:"A"`
+			B string `env:"B"`
+		}
+		err := envconfig.ReadContext(ctx, &cfg, envconfig.WithLookupContext(lookup))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("expected lookups to stop after cancellation, got %d calls", calls)
+		}
+	})
+
+	t.Run("collector_lookup_error", func(t *testing.T) {
+		lookup := func(ctx context.Context, key string) (string, bool, error) {
+			return "", false, errors.New("timeout")
+		}
+
+		var cfg struct {
+			Raw rawCollector
+		}
+		err := envconfig.ReadContext(context.Background(), &cfg, envconfig.WithLookupContext(lookup))
+		assertErr(t, err, `envconfig: "Raw" CollectEnv failed: envconfig: lookup "RAW": timeout`)
+
+		var required struct {
+			Raw requiredCollector
+		}
+		err = envconfig.ReadContext(context.Background(), &required, envconfig.WithLookupContext(lookup))
+		assertErr(t, err, `envconfig: "Raw" CollectEnv failed: envconfig: lookup "RAW": timeout`)
+	})
+}
+
+// rawCollector ignores the result of EnvGetter.Lookup, lookup errors must still surface.
+type rawCollector struct {
+	value string
+}
+
+func (r *rawCollector) CollectEnv(env envconfig.EnvGetter) error {
+	r.value, _ = env.Lookup("RAW")
+	return nil
+}
+
+// requiredCollector fails if RAW is not set, a lookup error must not be reported as missing.
+type requiredCollector struct {
+	value string
+}
+
+func (r *requiredCollector) CollectEnv(env envconfig.EnvGetter) error {
+	value, ok := env.Lookup("RAW")
+	if !ok {
+		return errors.New("RAW is required")
+	}
+	r.value = value
+	return nil
+}
@@ -0,0 +1,62 @@
+package envconfig
+
+import (
+	"reflect"
+	"sync"
+)
+
+// parserFunc parses a raw value into a value of the type it was registered for.
+type parserFunc func(value string) (reflect.Value, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[reflect.Type]parserFunc{}
+)
+
+// RegisterParser registers fn as the parser for values of type T in all Read calls,
+// including T used as slice, array and map elements or behind pointers.
+// Registered parsers take precedence over built-in parsing and unmarshal interfaces implemented by T.
+// Registering a parser for the same type again replaces it.
+//
+// Usage:
+//
+//	func init() {
+//	  envconfig.RegisterParser(uuid.Parse)
+//	}
+func RegisterParser[T any](fn func(string) (T, error)) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[reflect.TypeFor[T]()] = newParserFunc(fn)
+}
+
+// WithParser registers fn as the parser for values of type T for a single ReadWith call.
+// It takes precedence over parsers registered with RegisterParser.
+func WithParser[T any](fn func(string) (T, error)) Option {
+	return func(o *options) {
+		if o.parsers == nil {
+			o.parsers = make(map[reflect.Type]parserFunc)
+		}
+		o.parsers[reflect.TypeFor[T]()] = newParserFunc(fn)
+	}
+}
+
+func newParserFunc[T any](fn func(string) (T, error)) parserFunc {
+	return func(value string) (reflect.Value, error) {
+		v, err := fn(value)
+		return reflect.ValueOf(&v).Elem(), err
+	}
+}
+
+// findParser returns the parser for t, preferring local over globally registered ones.
+func findParser(local map[reflect.Type]parserFunc, t reflect.Type) (parserFunc, bool) {
+	if parse, ok := local[t]; ok {
+		return parse, true
+	}
+
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	parse, ok := parsers[t]
+	return parse, ok
+}
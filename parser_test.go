@@ -0,0 +1,151 @@
+package envconfig_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+// domainID is a type that does not implement any unmarshal interface.
+type domainID struct {
+	kind string
+	n    string
+}
+
+func parseDomainID(s string) (domainID, error) {
+	kind, n, ok := strings.Cut(s, "-")
+	if !ok {
+		return domainID{}, errors.New("invalid domain id " + s)
+	}
+	return domainID{kind: kind, n: n}, nil
+}
+
+// overriddenString implements json.Unmarshaler, a registered parser must win.
+type overriddenString string
+
+func (o *overriddenString) UnmarshalJSON([]byte) error {
+	*o = "unmarshaler"
+	return nil
+}
+
+func init() {
+	envconfig.RegisterParser(parseDomainID)
+	envconfig.RegisterParser(func(s string) (overriddenString, error) {
+		return overriddenString("parser:" + s), nil
+	})
+}
+
+func TestRegisterParser(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "ID":
+			return "user-42", true
+		case "IDS":
+			return "user-1, team-2", true
+		case "ID_MAP":
+			return "owner=user-1", true
+		case "OVERRIDDEN":
+			return "value", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		ID         domainID            `env:"ID"`
+		IDPtr      *domainID           `env:"ID"`
+		Missing    *domainID           `env:"MISSING"`
+		IDs        []domainID          `env:"IDS"`
+		IDArr      [2]domainID         `env:"IDS"`
+		IDMap      map[string]domainID `env:"ID_MAP"`
+		Overridden overriddenString    `env:"OVERRIDDEN"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	user42 := domainID{kind: "user", n: "42"}
+	if cfg.ID != user42 {
+		t.Errorf("ID: unexpected %+v", cfg.ID)
+	}
+	if cfg.IDPtr == nil || *cfg.IDPtr != user42 {
+		t.Errorf("IDPtr: unexpected %+v", cfg.IDPtr)
+	}
+	if cfg.Missing != nil {
+		t.Errorf("Missing: expected nil, got %+v", cfg.Missing)
+	}
+	if len(cfg.IDs) != 2 || cfg.IDs[1] != (domainID{kind: "team", n: "2"}) {
+		t.Errorf("IDs: unexpected %+v", cfg.IDs)
+	}
+	if cfg.IDArr[0] != (domainID{kind: "user", n: "1"}) {
+		t.Errorf("IDArr: unexpected %+v", cfg.IDArr)
+	}
+	if cfg.IDMap["owner"] != (domainID{kind: "user", n: "1"}) {
+		t.Errorf("IDMap: unexpected %+v", cfg.IDMap)
+	}
+	if cfg.Overridden != "parser:value" {
+		t.Errorf("Overridden: unexpected %q", cfg.Overridden)
+	}
+}
+
+func TestWithParser(t *testing.T) {
+	le := func(key string) (string, bool) {
+		if key == "ID" {
+			return "user-42", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		ID domainID `env:"ID"`
+	}
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithParser(func(s string) (domainID, error) {
+			return domainID{kind: "local", n: s}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ID != (domainID{kind: "local", n: "user-42"}) {
+		t.Errorf("expected local parser to take precedence, got %+v", cfg.ID)
+	}
+
+	var ptr struct {
+		ID      *domainID   `env:"ID"`
+		IDs     []*domainID `env:"ID"`
+		Missing *domainID   `env:"MISSING"`
+	}
+	err = envconfig.ReadWith(&ptr,
+		envconfig.WithLookup(le),
+		envconfig.WithParser(func(s string) (*domainID, error) {
+			return &domainID{kind: "ptr", n: s}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ptr.ID == nil || *ptr.ID != (domainID{kind: "ptr", n: "user-42"}) {
+		t.Errorf("ID: expected the pointer parser to be used, got %+v", ptr.ID)
+	}
+	if len(ptr.IDs) != 1 || *ptr.IDs[0] != (domainID{kind: "ptr", n: "user-42"}) {
+		t.Errorf("IDs: unexpected %+v", ptr.IDs)
+	}
+	if ptr.Missing != nil {
+		t.Errorf("Missing: expected nil, got %+v", ptr.Missing)
+	}
+}
+
+func TestRegisterParserError(t *testing.T) {
+	le := func(key string) (string, bool) {
+		return "invalid", true
+	}
+
+	var cfg struct {
+		ID domainID `env:"ID"`
+	}
+	err := envconfig.Read(&cfg, le)
+	assertErr(t, err, `envconfig: field "ID" failed to populate: invalid domain id invalid`)
+}
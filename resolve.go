@@ -0,0 +1,134 @@
+package envconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves the reference part of a value like "scheme:ref" or "scheme://ref" into the actual value.
+type Resolver func(ctx context.Context, ref string) (string, error)
+
+// literalScheme escapes values that would otherwise be resolved: "literal:file://x" is read as "file://x".
+const literalScheme = "literal"
+
+var (
+	resolversMu sync.RWMutex
+	resolvers   = map[string]Resolver{
+		"file":   resolveFile,
+		"base64": resolveBase64,
+		"env":    resolveEnv,
+	}
+)
+
+// RegisterResolver registers r for values starting with scheme + ":" in all Read calls.
+// Registering a resolver for the same scheme again replaces it.
+// References are only resolved for fields tagged with `envResolve:"true"`, or all fields with WithReferenceResolution.
+//
+// Built-in schemes:
+//   - file: file contents, e.g. "file:///etc/tls/cert.pem"
+//   - base64: standard base64 decoded data, e.g. "base64:aGVsbG8="
+//   - env: value of another key from the same lookup, e.g. "env://OTHER_VAR"
+func RegisterResolver(scheme string, r Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	resolvers[scheme] = r
+}
+
+// WithResolver registers r for scheme for a single ReadWith call.
+// It takes precedence over resolvers registered with RegisterResolver.
+func WithResolver(scheme string, r Resolver) Option {
+	return func(o *options) {
+		if o.resolvers == nil {
+			o.resolvers = make(map[string]Resolver)
+		}
+		o.resolvers[scheme] = r
+	}
+}
+
+// WithReferenceResolution resolves references in the values of all fields,
+// as if every field was tagged with `envResolve:"true"`. `envResolve:"false"` opts a field out.
+func WithReferenceResolution() Option {
+	return func(o *options) {
+		o.resolveRefs = true
+	}
+}
+
+func (o *options) findResolver(scheme string) (Resolver, bool) {
+	if r, ok := o.resolvers[scheme]; ok {
+		return r, true
+	}
+
+	resolversMu.RLock()
+	defer resolversMu.RUnlock()
+
+	r, ok := resolvers[scheme]
+	return r, ok
+}
+
+type lookupContextKey struct{}
+
+// resolveRef resolves value if it is a reference with a known scheme, a value is resolved once.
+// Values without a known scheme are returned as is, the "literal:" prefix is stripped.
+func (o *options) resolveRef(spec *fieldSpec, value string) (string, error) {
+	if !spec.resolve {
+		return value, nil
+	}
+
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+	if scheme == literalScheme {
+		return ref, nil
+	}
+
+	r, ok := o.findResolver(scheme)
+	if !ok {
+		return value, nil
+	}
+
+	ctx := context.WithValue(o.ctx, lookupContextKey{}, o.lookup)
+	resolved, err := r(ctx, strings.TrimPrefix(ref, "//"))
+	if err != nil {
+		return "", fmt.Errorf("envconfig: resolving %q: %w", spec.key, err)
+	}
+	return resolved, nil
+}
+
+func resolveFile(_ context.Context, path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func resolveBase64(_ context.Context, data string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// resolveEnv reads key with the lookup of the Read call, or os.LookupEnv outside of it.
+func resolveEnv(ctx context.Context, key string) (string, error) {
+	lookup, ok := ctx.Value(lookupContextKey{}).(LookupEnvContext)
+	if !ok {
+		lookup = adaptLookup(os.LookupEnv)
+	}
+
+	v, ok, err := lookup(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("referenced key %q is not set", key)
+	}
+	return v, nil
+}
@@ -0,0 +1,220 @@
+package envconfig_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestResolveReferences(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(certFile, []byte("-----BEGIN CERTIFICATE-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	le := func(key string) (string, bool) {
+		switch key {
+		case "TLS_CERT":
+			return "file://" + certFile, true
+		case "KEY":
+			return "base64:aGVsbG8=", true
+		case "ALIAS":
+			return "env://OTHER_VAR", true
+		case "OTHER_VAR":
+			return "other", true
+		case "LITERAL":
+			return "literal:base64:aGVsbG8=", true
+		case "URL":
+			return "http://example.com", true
+		case "PLAIN":
+			return "base64:aGVsbG8=", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		TLSCert string `env:"TLS_CERT" envResolve:"true"`
+		Key     []byte `env:"KEY" envResolve:"true"`
+		Alias   string `env:"ALIAS" envResolve:"true"`
+		Literal string `env:"LITERAL" envResolve:"true"`
+		URL     string `env:"URL" envResolve:"true"`
+		Default string `env:"MISSING" envResolve:"true" envDefault:"env://OTHER_VAR"`
+		Plain   string `env:"PLAIN"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.TLSCert != "-----BEGIN CERTIFICATE-----\n" {
+		t.Errorf("TLSCert: unexpected %q", cfg.TLSCert)
+	}
+	if string(cfg.Key) != "hello" {
+		t.Errorf("Key: unexpected %q", cfg.Key)
+	}
+	if cfg.Alias != "other" || cfg.Default != "other" {
+		t.Errorf("Alias: unexpected %q, %q", cfg.Alias, cfg.Default)
+	}
+	if cfg.Literal != "base64:aGVsbG8=" {
+		t.Errorf("Literal: unexpected %q", cfg.Literal)
+	}
+	if cfg.URL != "http://example.com" {
+		t.Errorf("URL: expected unknown scheme to be kept, got %q", cfg.URL)
+	}
+	if cfg.Plain != "base64:aGVsbG8=" {
+		t.Errorf("Plain: expected no resolution without opt-in, got %q", cfg.Plain)
+	}
+}
+
+func TestResolveReferencesOptions(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "PASSWORD":
+			return "vault://db/password", true
+		case "TOKEN":
+			return "base64:dG9rZW4=", true
+		case "RAW":
+			return "base64:dG9rZW4=", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Password string `env:"PASSWORD"`
+		Token    string `env:"TOKEN"`
+		Raw      string `env:"RAW" envResolve:"false"`
+	}
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithReferenceResolution(),
+		envconfig.WithResolver("vault", func(ctx context.Context, ref string) (string, error) {
+			return "secret-for-" + ref, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Password != "secret-for-db/password" {
+		t.Errorf("Password: unexpected %q", cfg.Password)
+	}
+	if cfg.Token != "token" {
+		t.Errorf("Token: unexpected %q", cfg.Token)
+	}
+	if cfg.Raw != "base64:dG9rZW4=" {
+		t.Errorf("Raw: expected opt-out, got %q", cfg.Raw)
+	}
+}
+
+func TestRegisterResolver(t *testing.T) {
+	envconfig.RegisterResolver("upper", func(ctx context.Context, ref string) (string, error) {
+		if ref == "" {
+			return "", errors.New("empty reference")
+		}
+		return strings.ToUpper(ref), nil
+	})
+
+	le := func(key string) (string, bool) {
+		switch key {
+		case "V":
+			return "upper:value", true
+		case "EMPTY":
+			return "upper:", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		V string `env:"V" envResolve:"true"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.V != "VALUE" {
+		t.Errorf("expected VALUE, got %q", cfg.V)
+	}
+
+	var failing struct {
+		V string `env:"EMPTY" envResolve:"true"`
+	}
+	err := envconfig.Read(&failing, le)
+	assertErr(t, err, `envconfig: resolving "EMPTY": empty reference`)
+}
+
+func TestResolveReferencesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVal  string
+		wantErr string
+	}{
+		{name: "missing_file", envVal: "file:///does/not/exist", wantErr: `envconfig: resolving "V": open /does/not/exist`},
+		{name: "invalid_base64", envVal: "base64:!!!", wantErr: `envconfig: resolving "V": illegal base64 data`},
+		{name: "missing_env", envVal: "env://NOPE", wantErr: `envconfig: resolving "V": referenced key "NOPE" is not set`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			le := func(key string) (string, bool) {
+				if key == "V" {
+					return tt.envVal, true
+				}
+				return "", false
+			}
+
+			var cfg struct {
+				V string `env:"V" envResolve:"true"`
+			}
+			err := envconfig.Read(&cfg, le)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveReferencesAfterDecodeHooks(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "PASSWORD", "PLAIN":
+			return "secret://db", true
+		}
+		return "", false
+	}
+
+	var seen []string
+	var cfg struct {
+		Password []byte `env:"PASSWORD"`
+		Plain    string `env:"PLAIN"`
+	}
+	err := envconfig.ReadWith(&cfg,
+		envconfig.WithLookup(le),
+		envconfig.WithReferenceResolution(),
+		envconfig.WithDecodeHook(func(f envconfig.FieldInfo, v string) (string, error) {
+			seen = append(seen, v)
+			// Resolve secret:// references only for []byte fields.
+			if ref, ok := strings.CutPrefix(v, "secret://"); ok && f.Type == reflect.TypeOf([]byte(nil)) {
+				return "base64:" + base64.StdEncoding.EncodeToString([]byte("pw-"+ref)), nil
+			}
+			return v, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(seen) != 2 || seen[0] != "secret://db" || seen[1] != "secret://db" {
+		t.Errorf("expected hooks to receive unresolved references, got %q", seen)
+	}
+	if string(cfg.Password) != "pw-db" {
+		t.Errorf("Password: expected the hook's reference to be resolved, got %q", cfg.Password)
+	}
+	if cfg.Plain != "secret://db" {
+		t.Errorf("Plain: unexpected %q", cfg.Plain)
+	}
+}
@@ -0,0 +1,81 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// timeLayouts maps the named layouts accepted by the `envLayout` tag to time layouts.
+// Names not listed here are used as a layout verbatim, e.g. `envLayout:"2006-01-02"`.
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+func setTime(inp reflect.Value, value string, spec *fieldSpec) error {
+	t, err := parseTime(value, spec)
+	if err != nil {
+		return err
+	}
+	inp.Set(reflect.ValueOf(t))
+	return nil
+}
+
+func setLocation(inp reflect.Value, value string, _ *fieldSpec) error {
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return err
+	}
+	inp.Set(reflect.ValueOf(loc))
+	return nil
+}
+
+// parseTime parses value according to spec.layout (RFC 3339 by default).
+// The "unix" and "unixms" layouts read seconds and milliseconds since the Unix epoch.
+// Values without zone information are interpreted in spec.location, or UTC if not set.
+func parseTime(value string, spec *fieldSpec) (time.Time, error) {
+	loc := time.UTC
+	if spec.location != nil {
+		loc = spec.location
+	}
+
+	switch spec.layout {
+	case "unix", "unixms":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %s timestamp %q: %w", spec.layout, value, err)
+		}
+		if spec.layout == "unix" {
+			return time.Unix(n, 0).In(loc), nil
+		}
+		return time.UnixMilli(n).In(loc), nil
+	}
+
+	layout := time.RFC3339
+	if spec.layout != "" {
+		layout = spec.layout
+		if named, ok := timeLayouts[layout]; ok {
+			layout = named
+		}
+	}
+
+	return time.ParseInLocation(layout, value, loc)
+}
@@ -0,0 +1,132 @@
+package envconfig_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadTime(t *testing.T) {
+	warsaw, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+
+	le := func(key string) (string, bool) {
+		switch key {
+		case "DEFAULT":
+			return "2026-01-27T10:00:00Z", true
+		case "DATE":
+			return "2026-01-27", true
+		case "NAMED":
+			return "Tue, 27 Jan 2026 10:00:00 GMT", true
+		case "UNIX":
+			return "1769508000", true
+		case "UNIX_MS":
+			return "1769508000123", true
+		case "TZ":
+			return "Europe/Warsaw", true
+		case "DATES":
+			return "2026-01-27, 2026-01-28", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Default  time.Time      `env:"DEFAULT"`
+		Date     time.Time      `env:"DATE" envLayout:"2006-01-02"`
+		DateTZ   time.Time      `env:"DATE" envLayout:"DateOnly" envTimezone:"Europe/Warsaw"`
+		Named    time.Time      `env:"NAMED" envLayout:"RFC1123"`
+		Unix     time.Time      `env:"UNIX" envLayout:"unix"`
+		UnixMs   *time.Time     `env:"UNIX_MS" envLayout:"unixms"`
+		Missing  *time.Time     `env:"MISSING"`
+		Dates    []time.Time    `env:"DATES" envLayout:"2006-01-02"`
+		Location *time.Location `env:"TZ"`
+		NoLoc    *time.Location `env:"MISSING"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	want := time.Date(2026, 1, 27, 10, 0, 0, 0, time.UTC)
+	date := time.Date(2026, 1, 27, 0, 0, 0, 0, time.UTC)
+
+	if !cfg.Default.Equal(want) {
+		t.Errorf("Default: expected %v, got %v", want, cfg.Default)
+	}
+	if !cfg.Date.Equal(date) {
+		t.Errorf("Date: expected %v, got %v", date, cfg.Date)
+	}
+	if dateTZ := time.Date(2026, 1, 27, 0, 0, 0, 0, warsaw); !cfg.DateTZ.Equal(dateTZ) {
+		t.Errorf("DateTZ: expected %v, got %v", dateTZ, cfg.DateTZ)
+	}
+	if !cfg.Named.Equal(want) {
+		t.Errorf("Named: expected %v, got %v", want, cfg.Named)
+	}
+	if !cfg.Unix.Equal(want) {
+		t.Errorf("Unix: expected %v, got %v", want, cfg.Unix)
+	}
+	if cfg.UnixMs == nil || !cfg.UnixMs.Equal(want.Add(123*time.Millisecond)) {
+		t.Errorf("UnixMs: expected %v, got %v", want.Add(123*time.Millisecond), cfg.UnixMs)
+	}
+	if cfg.Missing != nil {
+		t.Errorf("Missing: expected nil, got %v", cfg.Missing)
+	}
+	if len(cfg.Dates) != 2 || !cfg.Dates[1].Equal(date.AddDate(0, 0, 1)) {
+		t.Errorf("Dates: unexpected %v", cfg.Dates)
+	}
+	if cfg.Location == nil || cfg.Location.String() != "Europe/Warsaw" {
+		t.Errorf("Location: expected Europe/Warsaw, got %v", cfg.Location)
+	}
+	if cfg.NoLoc != nil {
+		t.Errorf("NoLoc: expected nil, got %v", cfg.NoLoc)
+	}
+}
+
+func TestReadTimeErrors(t *testing.T) {
+	le := func(key string) (string, bool) {
+		return "not-a-time", true
+	}
+
+	t.Run("invalid_time", func(t *testing.T) {
+		var cfg struct {
+			V time.Time `env:"V" envLayout:"DateOnly"`
+		}
+		err := envconfig.Read(&cfg, le)
+		if err == nil || !strings.Contains(err.Error(), `envconfig: field "V" failed to populate`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid_unix", func(t *testing.T) {
+		var cfg struct {
+			V time.Time `env:"V" envLayout:"unix"`
+		}
+		err := envconfig.Read(&cfg, le)
+		if err == nil || !strings.Contains(err.Error(), `invalid unix timestamp "not-a-time"`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid_timezone_tag", func(t *testing.T) {
+		var cfg struct {
+			V time.Time `env:"V" envTimezone:"Nowhere/Special"`
+		}
+		err := envconfig.Read(&cfg, le)
+		if err == nil || !strings.Contains(err.Error(), `envconfig: field "V": invalid "envTimezone" tag`) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("invalid_location", func(t *testing.T) {
+		var cfg struct {
+			V *time.Location `env:"V"`
+		}
+		err := envconfig.Read(&cfg, le)
+		if err == nil || !strings.Contains(err.Error(), "unknown time zone") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
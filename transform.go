@@ -0,0 +1,31 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// transform applies the `envTrim`, `envCase` and `envExpandHome` tags to value, in that order.
+func (s *fieldSpec) transform(value string) (string, error) {
+	if s.trim {
+		value = strings.TrimSpace(value)
+	}
+
+	switch s.caseMode {
+	case "lower":
+		value = strings.ToLower(value)
+	case "upper":
+		value = strings.ToUpper(value)
+	}
+
+	if s.expandHome && (value == "~" || strings.HasPrefix(value, "~/")) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("%q: can't expand home directory: %w", s.key, err)
+		}
+		value = home + value[1:]
+	}
+
+	return value, nil
+}
@@ -0,0 +1,92 @@
+package envconfig_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadTransforms(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	le := func(key string) (string, bool) {
+		switch key {
+		case "LOG_LEVEL":
+			return "Info ", true
+		case "REGION":
+			return " eu-west-1", true
+		case "DATA_DIR":
+			return "~/data", true
+		case "CACHE_DIR":
+			return "~/cache/", true
+		case "RELATIVE":
+			return "~/a/../b", true
+		case "PATHS":
+			return "~/a, /b, ~", true
+		case "LEVELS":
+			return "API=Debug, DB=WARN", true
+		case "RAW":
+			return " Raw ", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		LogLevel string            `env:"LOG_LEVEL" envTrim:"true" envCase:"lower"`
+		Region   string            `env:"REGION" envTrim:"true" envCase:"upper"`
+		DataDir  string            `env:"DATA_DIR" envExpandHome:"true"`
+		CacheDir string            `env:"CACHE_DIR" envExpandHome:"true"`
+		Relative string            `env:"RELATIVE" envExpandHome:"true"`
+		Default  string            `env:"MISSING" envDefault:"~" envExpandHome:"true"`
+		Paths    []string          `env:"PATHS" envExpandHome:"true"`
+		Levels   map[string]string `env:"LEVELS" envCase:"lower"`
+		Raw      string            `env:"RAW"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.LogLevel != "info" {
+		t.Errorf("LogLevel: expected %q, got %q", "info", cfg.LogLevel)
+	}
+	if cfg.Region != "EU-WEST-1" {
+		t.Errorf("Region: expected %q, got %q", "EU-WEST-1", cfg.Region)
+	}
+	if want := home + "/data"; cfg.DataDir != want {
+		t.Errorf("DataDir: expected %q, got %q", want, cfg.DataDir)
+	}
+	if want := home + "/cache/"; cfg.CacheDir != want {
+		t.Errorf("CacheDir: expected the trailing slash to be kept, got %q", cfg.CacheDir)
+	}
+	if want := home + "/a/../b"; cfg.Relative != want {
+		t.Errorf("Relative: expected the path not to be cleaned, got %q", cfg.Relative)
+	}
+	if cfg.Default != home {
+		t.Errorf("Default: expected %q, got %q", home, cfg.Default)
+	}
+	if len(cfg.Paths) != 3 || cfg.Paths[0] != home+"/a" || cfg.Paths[1] != "/b" || cfg.Paths[2] != home {
+		t.Errorf("Paths: unexpected %q", cfg.Paths)
+	}
+	if cfg.Levels["api"] != "debug" || cfg.Levels["db"] != "warn" {
+		t.Errorf("Levels: unexpected %v", cfg.Levels)
+	}
+	if cfg.Raw != " Raw " {
+		t.Errorf("Raw: expected untouched value, got %q", cfg.Raw)
+	}
+}
+
+func TestReadTransformsInvalidCase(t *testing.T) {
+	le := func(key string) (string, bool) {
+		return "value", true
+	}
+
+	var cfg struct {
+		V string `env:"V" envCase:"title"`
+	}
+	err := envconfig.Read(&cfg, le)
+	assertErr(t, err, `envconfig: field "V": invalid "envCase" tag "title", expected "lower" or "upper"`)
+}
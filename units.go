@@ -0,0 +1,183 @@
+package envconfig
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unitBytes is the `envUnit` value enabling human-friendly byte sizes on integer fields.
+const unitBytes = "bytes"
+
+// byteUnits maps lower-cased size suffixes to their multipliers.
+// SI suffixes (KB, MB, ...) are powers of 1000, IEC suffixes (KiB, MiB, ...) are powers of 1024.
+var byteUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"eb":  1e18,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+	"pib": 1 << 50,
+	"eib": 1 << 60,
+}
+
+// parseBytes parses sizes like "512", "10MiB", "1.5 GB" into a number of bytes not greater than maxVal.
+func parseBytes(value string, maxVal uint64) (uint64, error) {
+	numEnd := strings.IndexFunc(value, func(r rune) bool { return r != '.' && (r < '0' || r > '9') })
+	if numEnd < 0 {
+		numEnd = len(value)
+	}
+	num, unit := value[:numEnd], strings.TrimSpace(value[numEnd:])
+
+	mult, ok := byteUnits[strings.ToLower(unit)]
+	if !ok || num == "" || num == "." {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+
+	whole, frac, _ := strings.Cut(num, ".")
+	if strings.Contains(frac, ".") {
+		return 0, fmt.Errorf("invalid byte size %q", value)
+	}
+
+	var n uint64
+	if whole != "" {
+		w, err := strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("byte size %q: %w", value, strconv.ErrRange)
+		}
+		hi, lo := bits.Mul64(w, mult)
+		if hi != 0 {
+			return 0, fmt.Errorf("byte size %q: %w", value, strconv.ErrRange)
+		}
+		n = lo
+	}
+
+	if frac != "" {
+		// 10^19 overflows uint64, precision beyond 18 digits is irrelevant anyway.
+		frac = frac[:min(len(frac), 18)]
+		f, err := strconv.ParseUint(frac, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid byte size %q", value)
+		}
+		scale := uint64(math.Pow10(len(frac)))
+		hi, lo := bits.Mul64(f, mult)
+		fracBytes, _ := bits.Div64(hi, lo, scale)
+
+		var carry uint64
+		n, carry = bits.Add64(n, fracBytes, 0)
+		if carry != 0 {
+			return 0, fmt.Errorf("byte size %q: %w", value, strconv.ErrRange)
+		}
+	}
+
+	if n > maxVal {
+		return 0, fmt.Errorf("byte size %q: %w", value, strconv.ErrRange)
+	}
+	return n, nil
+}
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// parseDuration extends time.ParseDuration with "d" (24h) and "w" (7d) units, e.g. "7d", "2w", "1d12h".
+func parseDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err == nil || !strings.ContainsAny(value, "dw") {
+		return d, err
+	}
+
+	s := value
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	isNum := func(r rune) bool { return r == '.' || (r >= '0' && r <= '9') }
+
+	var total time.Duration
+	for s != "" {
+		numEnd := strings.IndexFunc(s, func(r rune) bool { return !isNum(r) })
+		if numEnd <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		unitEnd := strings.IndexFunc(s[numEnd:], isNum)
+		if unitEnd < 0 {
+			unitEnd = len(s) - numEnd
+		}
+		num, unit := s[:numEnd], s[numEnd:numEnd+unitEnd]
+		s = s[numEnd+unitEnd:]
+
+		var part time.Duration
+		switch unit {
+		case "d":
+			part, err = scaleDuration(num, day)
+		case "w":
+			part, err = scaleDuration(num, week)
+		default:
+			part, err = time.ParseDuration(num + unit)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+
+		if total > math.MaxInt64-part {
+			return 0, fmt.Errorf("invalid duration %q: %w", value, strconv.ErrRange)
+		}
+		total += part
+	}
+
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// scaleDuration returns num (a decimal number) multiplied by unit.
+func scaleDuration(num string, unit time.Duration) (time.Duration, error) {
+	whole, frac, _ := strings.Cut(num, ".")
+	if whole == "" && frac == "" {
+		return 0, strconv.ErrSyntax
+	}
+
+	var d time.Duration
+	if whole != "" {
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if w > int64(math.MaxInt64/unit) {
+			return 0, strconv.ErrRange
+		}
+		d = time.Duration(w) * unit
+	}
+
+	if frac != "" {
+		f, err := strconv.ParseFloat("0."+frac, 64)
+		if err != nil {
+			return 0, err
+		}
+		fracPart := time.Duration(f * float64(unit))
+		if d > math.MaxInt64-fracPart {
+			return 0, strconv.ErrRange
+		}
+		d += fracPart
+	}
+
+	return d, nil
+}
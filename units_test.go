@@ -0,0 +1,201 @@
+package envconfig_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/struct0x/envconfig"
+)
+
+func TestReadByteSizes(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "PLAIN":
+			return "512", true
+		case "SI":
+			return "10MB", true
+		case "IEC":
+			return "10MiB", true
+		case "FRACTION":
+			return "1.5 GiB", true
+		case "LOWER":
+			return "4kib", true
+		case "SIZES":
+			return "1KiB, 2KiB", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Plain    int64   `env:"PLAIN" envUnit:"bytes"`
+		SI       uint64  `env:"SI" envUnit:"bytes"`
+		IEC      int     `env:"IEC" envUnit:"bytes"`
+		Fraction *uint64 `env:"FRACTION" envUnit:"bytes"`
+		Lower    uint32  `env:"LOWER" envUnit:"bytes"`
+		Sizes    []int   `env:"SIZES" envUnit:"bytes"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Plain != 512 {
+		t.Errorf("Plain: expected 512, got %d", cfg.Plain)
+	}
+	if cfg.SI != 10_000_000 {
+		t.Errorf("SI: expected 10000000, got %d", cfg.SI)
+	}
+	if cfg.IEC != 10<<20 {
+		t.Errorf("IEC: expected %d, got %d", 10<<20, cfg.IEC)
+	}
+	if cfg.Fraction == nil || *cfg.Fraction != 3<<29 {
+		t.Errorf("Fraction: expected %d, got %v", 3<<29, cfg.Fraction)
+	}
+	if cfg.Lower != 4096 {
+		t.Errorf("Lower: expected 4096, got %d", cfg.Lower)
+	}
+	if len(cfg.Sizes) != 2 || cfg.Sizes[1] != 2048 {
+		t.Errorf("Sizes: unexpected %v", cfg.Sizes)
+	}
+}
+
+func TestReadExtendedDurations(t *testing.T) {
+	le := func(key string) (string, bool) {
+		switch key {
+		case "DAYS":
+			return "7d", true
+		case "WEEKS":
+			return "2w", true
+		case "MIXED":
+			return "1d12h30m", true
+		case "FRACTION":
+			return "1.5d", true
+		case "NEGATIVE":
+			return "-1w", true
+		case "STANDARD":
+			return "90m", true
+		}
+		return "", false
+	}
+
+	var cfg struct {
+		Days     time.Duration  `env:"DAYS"`
+		Weeks    time.Duration  `env:"WEEKS"`
+		Mixed    time.Duration  `env:"MIXED"`
+		Fraction *time.Duration `env:"FRACTION"`
+		Negative time.Duration  `env:"NEGATIVE"`
+		Standard time.Duration  `env:"STANDARD"`
+	}
+	if err := envconfig.Read(&cfg, le); err != nil {
+		t.Fatal(err)
+	}
+
+	const day = 24 * time.Hour
+	if cfg.Days != 7*day {
+		t.Errorf("Days: expected %v, got %v", 7*day, cfg.Days)
+	}
+	if cfg.Weeks != 14*day {
+		t.Errorf("Weeks: expected %v, got %v", 14*day, cfg.Weeks)
+	}
+	if want := day + 12*time.Hour + 30*time.Minute; cfg.Mixed != want {
+		t.Errorf("Mixed: expected %v, got %v", want, cfg.Mixed)
+	}
+	if cfg.Fraction == nil || *cfg.Fraction != 36*time.Hour {
+		t.Errorf("Fraction: expected %v, got %v", 36*time.Hour, cfg.Fraction)
+	}
+	if cfg.Negative != -7*day {
+		t.Errorf("Negative: expected %v, got %v", -7*day, cfg.Negative)
+	}
+	if cfg.Standard != 90*time.Minute {
+		t.Errorf("Standard: expected %v, got %v", 90*time.Minute, cfg.Standard)
+	}
+}
+
+func TestReadUnitsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sut     func(le envconfig.LookupEnv) error
+		envVal  string
+		wantErr string
+	}{
+		{
+			name: "unknown_size_unit",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V int `env:"V" envUnit:"bytes"`
+				}{}, le)
+			},
+			envVal:  "10XB",
+			wantErr: `invalid byte size "10XB"`,
+		},
+		{
+			name: "size_overflow_int8",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V int8 `env:"V" envUnit:"bytes"`
+				}{}, le)
+			},
+			envVal:  "1KiB",
+			wantErr: "out of range",
+		},
+		{
+			name: "size_overflow_uint64",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V uint64 `env:"V" envUnit:"bytes"`
+				}{}, le)
+			},
+			envVal:  "16EiB",
+			wantErr: "out of range",
+		},
+		{
+			name: "unsupported_unit_tag",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V int `env:"V" envUnit:"bits"`
+				}{}, le)
+			},
+			envVal:  "1",
+			wantErr: `envconfig: field "V": unsupported "envUnit" tag "bits"`,
+		},
+		{
+			name: "duration_overflow",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V time.Duration `env:"V"`
+				}{}, le)
+			},
+			envVal:  "20000w",
+			wantErr: "out of range",
+		},
+		{
+			name: "invalid_day_duration",
+			sut: func(le envconfig.LookupEnv) error {
+				return envconfig.Read(&struct {
+					V time.Duration `env:"V"`
+				}{}, le)
+			},
+			envVal:  "1.2.3d",
+			wantErr: "invalid duration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			le := func(key string) (string, bool) {
+				if key == "V" {
+					return tt.envVal, true
+				}
+				return "", false
+			}
+
+			err := tt.sut(le)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}